@@ -0,0 +1,312 @@
+package gotoken
+
+import (
+	"errors"
+	"html"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// CharFilter transforms the source before tokenization. It also returns the
+// correction which maps byte offsets of the result back to the source, nil if
+// offsets are kept.
+type CharFilter interface {
+	FilterString(source string) (string, *OffsetCorrection)
+}
+
+// OffsetCorrection maps byte offsets of a filtered text to byte offsets of the
+// text before the filter. Every replacement shifts the offsets after it: an
+// offset at the start of a replacement maps to the start of the replaced text,
+// an offset at its end maps to the end of the replaced text. A nil correction
+// keeps offsets as is.
+type OffsetCorrection struct {
+	offsets []int // Offsets of the filtered text where the shift changes, ascending.
+	shifts  []int // Shift of the offsets starting from there.
+}
+
+// Correct maps the offset of the filtered text to the offset of the source.
+func (c *OffsetCorrection) Correct(offset int) int {
+	if c == nil {
+		return offset
+	}
+	index := sort.SearchInts(c.offsets, offset+1) - 1
+	if index < 0 {
+		return offset
+	}
+	return offset + c.shifts[index]
+}
+
+// correctedBuilder builds a filtered text and its offset correction.
+type correctedBuilder struct {
+	source     string
+	text       strings.Builder
+	correction *OffsetCorrection
+	copied     int // Bytes of the source processed so far.
+}
+
+// replace copies the source up to start and writes the replacement of
+// source[start:end].
+func (b *correctedBuilder) replace(start int, end int, replacement string) {
+	b.text.WriteString(b.source[b.copied:start])
+	b.text.WriteString(replacement)
+	b.copied = end
+	if end-start == len(replacement) {
+		return
+	}
+	if b.correction == nil {
+		b.correction = &OffsetCorrection{}
+	}
+	b.correction.offsets = append(b.correction.offsets, b.text.Len())
+	b.correction.shifts = append(b.correction.shifts, end-b.text.Len())
+}
+
+func (b *correctedBuilder) result() (string, *OffsetCorrection) {
+	if b.copied == 0 && b.text.Len() == 0 {
+		return b.source, nil
+	}
+	b.text.WriteString(b.source[b.copied:])
+	return b.text.String(), b.correction
+}
+
+// TokenFilter transforms subtokens produced by the tokenizer.
+type TokenFilter interface {
+	FilterTokens(tokens []SmartTokenOccurrence) []SmartTokenOccurrence
+}
+
+// Analyzer is a chain of character filters, SmartToken tokenizer and token
+// filters. Offsets of the result refer to the source before character
+// filters, so they can be used to highlight the original document.
+// An analyzer with a configured tokenizer is safe for concurrent use.
+type Analyzer struct {
+	CharFilters  []CharFilter
+	Tokenizer    *SmartToken
+	TokenFilters []TokenFilter
+}
+
+// AnalyzerConfig describes an analyzer built of standard filters.
+type AnalyzerConfig struct {
+	Tokenizer *SmartToken         // Required.
+	StripHTML bool                // Remove HTML tags and unescape entities.
+	Mapping   map[string]string   // Replace substrings of the source.
+	Lowercase bool                // Lowercase subtokens.
+	StopWords StopWords           // Drop subtokens which are stop words.
+	MinLength int                 // Drop subtokens shorter than MinLength runes.
+	MaxLength int                 // Drop subtokens longer than MaxLength runes, zero for no limit.
+	Synonyms  map[string][]string // Add synonyms of subtokens at the same positions.
+}
+
+// NewAnalyzer builds an analyzer from the config. Character filters go in the
+// order HTML stripping, mapping; token filters go in the order lowercasing,
+// stop words, length limits, synonyms.
+func NewAnalyzer(config AnalyzerConfig) (*Analyzer, error) {
+	if config.Tokenizer == nil {
+		return nil, errors.New("gotoken: analyzer needs a tokenizer")
+	}
+	if config.MaxLength != 0 && config.MaxLength < config.MinLength {
+		return nil, errors.New("gotoken: analyzer MaxLength is less than MinLength")
+	}
+
+	a := &Analyzer{Tokenizer: config.Tokenizer}
+	if config.StripHTML {
+		a.CharFilters = append(a.CharFilters, HTMLStripFilter{})
+	}
+	if len(config.Mapping) > 0 {
+		a.CharFilters = append(a.CharFilters, NewMappingFilter(config.Mapping))
+	}
+	if config.Lowercase {
+		a.TokenFilters = append(a.TokenFilters, LowercaseFilter{})
+	}
+	if len(config.StopWords) > 0 {
+		a.TokenFilters = append(a.TokenFilters, StopFilter{Words: config.StopWords})
+	}
+	if config.MinLength > 0 || config.MaxLength > 0 {
+		a.TokenFilters = append(a.TokenFilters, LengthFilter{Min: config.MinLength, Max: config.MaxLength})
+	}
+	if len(config.Synonyms) > 0 {
+		a.TokenFilters = append(a.TokenFilters, SynonymFilter{Synonyms: config.Synonyms})
+	}
+	return a, nil
+}
+
+// Analyze runs the chain on the source. Byte and rune offsets of the result
+// and detected bases are mapped back to the source through corrections of the
+// character filters.
+func (a *Analyzer) Analyze(source string) []SmartTokenOccurrence {
+	text := source
+	var corrections []*OffsetCorrection
+	for _, filter := range a.CharFilters {
+		var correction *OffsetCorrection
+		text, correction = filter.FilterString(text)
+		if correction != nil {
+			corrections = append(corrections, correction)
+		}
+	}
+	tokens := a.Tokenizer.TokenizeStringOccurrences(text)
+	if len(corrections) > 0 {
+		correct := func(offset int) int {
+			for index := len(corrections) - 1; index >= 0; index-- {
+				offset = corrections[index].Correct(offset)
+			}
+			return offset
+		}
+		runes := runeOffsets(source)
+		runeCount := func(offset int) int {
+			if offset < len(source) && !utf8.RuneStart(source[offset]) {
+				return utf8.RuneCountInString(source[:offset])
+			}
+			return runes[offset]
+		}
+		for index := range tokens {
+			token := &tokens[index]
+			start, end := correct(token.ByteStart), correct(token.ByteEnd)
+			baseLeft := correct(token.ByteStart + token.Info.DetectedBase[0])
+			baseRight := correct(token.ByteStart + token.Info.DetectedBase[1])
+			token.Info.DetectedBase = [2]int{baseLeft - start, baseRight - start}
+			token.ByteStart, token.ByteEnd = start, end
+			token.RuneStart, token.RuneEnd = runeCount(start), runeCount(end)
+		}
+	}
+	for _, filter := range a.TokenFilters {
+		tokens = filter.FilterTokens(tokens)
+	}
+	return tokens
+}
+
+// HTMLStripFilter replaces HTML tags, comments, scripts and styles with spaces
+// and unescapes entities.
+type HTMLStripFilter struct{}
+
+var (
+	htmlMarkup = regexp.MustCompile(`(?is)<!--.*?-->|<script\b.*?</script\s*>|<style\b.*?</style\s*>|</?[a-z!][^>]*>`)
+	htmlEntity = regexp.MustCompile(`&(?:#[0-9]+|#[xX][0-9a-fA-F]+|[a-zA-Z][a-zA-Z0-9]*);?`)
+)
+
+func (HTMLStripFilter) FilterString(source string) (string, *OffsetCorrection) {
+	b := &correctedBuilder{source: source}
+	markup := htmlMarkup.FindAllStringIndex(source, -1)
+	for _, entity := range htmlEntity.FindAllStringIndex(source, -1) {
+		for len(markup) > 0 && markup[0][1] <= entity[0] {
+			b.replace(markup[0][0], markup[0][1], " ")
+			markup = markup[1:]
+		}
+		if entity[0] < b.copied || len(markup) > 0 && markup[0][0] < entity[1] {
+			continue // Inside markup.
+		}
+		text := source[entity[0]:entity[1]]
+		if unescaped := html.UnescapeString(text); unescaped != text {
+			b.replace(entity[0], entity[1], unescaped)
+		}
+	}
+	for _, m := range markup {
+		b.replace(m[0], m[1], " ")
+	}
+	return b.result()
+}
+
+// MappingFilter replaces substrings of the source. Longer substrings win.
+type MappingFilter struct {
+	mapping map[string]string
+	keys    map[byte][]string // First byte -> Keys starting with it, longer first.
+}
+
+// NewMappingFilter creates a filter replacing keys of mapping with its values.
+func NewMappingFilter(mapping map[string]string) *MappingFilter {
+	f := &MappingFilter{mapping: make(map[string]string), keys: make(map[byte][]string)}
+	for key, value := range mapping {
+		if key != "" {
+			f.mapping[key] = value
+			f.keys[key[0]] = append(f.keys[key[0]], key)
+		}
+	}
+	for _, keys := range f.keys {
+		sort.Slice(keys, func(i, j int) bool {
+			if len(keys[i]) != len(keys[j]) {
+				return len(keys[i]) > len(keys[j])
+			}
+			return keys[i] < keys[j]
+		})
+	}
+	return f
+}
+
+func (f *MappingFilter) FilterString(source string) (string, *OffsetCorrection) {
+	b := &correctedBuilder{source: source}
+	for index := 0; index < len(source); {
+		matched := false
+		for _, key := range f.keys[source[index]] {
+			if strings.HasPrefix(source[index:], key) {
+				b.replace(index, index+len(key), f.mapping[key])
+				index += len(key)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			index++
+		}
+	}
+	return b.result()
+}
+
+// LowercaseFilter lowercases subtokens.
+type LowercaseFilter struct{}
+
+func (LowercaseFilter) FilterTokens(tokens []SmartTokenOccurrence) []SmartTokenOccurrence {
+	for index := range tokens {
+		tokens[index].Token = strings.ToLower(tokens[index].Token)
+	}
+	return tokens
+}
+
+// StopFilter drops subtokens which are stop words.
+type StopFilter struct {
+	Words StopWords
+}
+
+func (f StopFilter) FilterTokens(tokens []SmartTokenOccurrence) []SmartTokenOccurrence {
+	result := tokens[:0]
+	for _, token := range tokens {
+		if !f.Words[strings.ToLower(token.Token)] {
+			result = append(result, token)
+		}
+	}
+	return result
+}
+
+// LengthFilter drops subtokens shorter than Min or longer than Max runes.
+// Zero Max means no limit.
+type LengthFilter struct {
+	Min int
+	Max int
+}
+
+func (f LengthFilter) FilterTokens(tokens []SmartTokenOccurrence) []SmartTokenOccurrence {
+	result := tokens[:0]
+	for _, token := range tokens {
+		length := utf8.RuneCountInString(token.Token)
+		if length >= f.Min && (f.Max == 0 || length <= f.Max) {
+			result = append(result, token)
+		}
+	}
+	return result
+}
+
+// SynonymFilter adds synonyms right after every subtoken which has them.
+// Synonyms share the position and the info of the original subtoken.
+type SynonymFilter struct {
+	Synonyms map[string][]string
+}
+
+func (f SynonymFilter) FilterTokens(tokens []SmartTokenOccurrence) []SmartTokenOccurrence {
+	var result []SmartTokenOccurrence
+	for _, token := range tokens {
+		result = append(result, token)
+		for _, synonym := range f.Synonyms[token.Token] {
+			token.Token = synonym
+			result = append(result, token)
+		}
+	}
+	return result
+}
@@ -0,0 +1,88 @@
+package gotoken
+
+import (
+	"sync"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzer(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+	st.AddRangeTable(unicode.Cyrillic)
+
+	_, err := NewAnalyzer(AnalyzerConfig{})
+	assert.Error(err)
+	_, err = NewAnalyzer(AnalyzerConfig{Tokenizer: st, MinLength: 3, MaxLength: 2})
+	assert.Error(err)
+
+	a, err := NewAnalyzer(AnalyzerConfig{
+		Tokenizer: st,
+		StripHTML: true,
+		Mapping:   map[string]string{"Ё": "Е", "ё": "е"},
+		Lowercase: true,
+		StopWords: StopWords{"world": true},
+		MinLength: 2,
+		Synonyms:  map[string][]string{"hello": []string{"hi", "hey"}},
+	})
+	assert.NoError(err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tokens := a.Analyze("<p>Hello <b>World</b></p><!-- x --> Ёж&amp;")
+			var texts []string
+			for _, token := range tokens {
+				texts = append(texts, token.Token)
+			}
+			assert.Equal([]string{"hello", "hi", "hey", "еж", "еж&"}, texts)
+			assert.Equal(3, tokens[0].ByteStart, "offsets refer to the source")
+			assert.Equal(tokens[0].ByteStart, tokens[1].ByteStart, "synonym position")
+		}()
+	}
+	wg.Wait()
+}
+
+func TestAnalyzerOffsets(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+
+	a, err := NewAnalyzer(AnalyzerConfig{
+		Tokenizer: st,
+		StripHTML: true,
+		Mapping:   map[string]string{"ß": "ss", "&": ""},
+	})
+	assert.NoError(err)
+
+	source := "<p title=\"a&amp;b\">caf&eacute;</p> straße &lt;x&gt;"
+	expected := map[string]string{
+		"café":    "caf&eacute;",
+		"strasse": "straße",
+		"<":       "&lt;",
+		"<x>":     "&lt;x&gt;",
+	}
+	found := 0
+	for _, token := range a.Analyze(source) {
+		if original, ok := expected[token.Token]; ok {
+			found++
+			assert.Equal(original, source[token.ByteStart:token.ByteEnd], "source of '%v'", token.Token)
+			assert.Equal(utf8.RuneCountInString(source[:token.ByteStart]), token.RuneStart)
+			assert.Equal(utf8.RuneCountInString(source[:token.ByteEnd]), token.RuneEnd)
+		}
+		if token.Token == "café" {
+			assert.Equal("caf&eacute;", source[token.ByteStart+token.Info.DetectedBase[0]:token.ByteStart+token.Info.DetectedBase[1]])
+		}
+	}
+	assert.Equal(len(expected), found)
+
+	text, correction := HTMLStripFilter{}.FilterString("plain")
+	assert.Equal("plain", text)
+	assert.Nil(correction)
+}
@@ -0,0 +1,341 @@
+// Command gotoken tokenizes files or the standard input with the SmartToken
+// algorithm and prints subtokens with their languages and bases.
+//
+// Usage:
+//
+//	gotoken [flags] [file ...]
+//
+// The policy is given as a type followed by parameters:
+//
+//	depth:maxLength,maxDepth,minLength,minDepth
+//	count:max
+//	step:length=depth,length=depth,...
+//	log:maxDepth,minDepth,scale
+//	blocks:maxBlocks,minDepth
+//
+// Languages are given as name=Script+Script pairs separated by commas, for
+// example "en=Latin,ru=Cyrillic". A lone script name is used as the name of
+// the language. Flags override the config file.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/rvncerr/gotoken"
+)
+
+const defaultPolicy = "depth:10,10,18,2"
+
+// record is a line of the JSON Lines output.
+type record struct {
+	File      string `json:"file,omitempty"`
+	Token     string `json:"token"`
+	Language  string `json:"language"`
+	Base      string `json:"base"`
+	ByteStart int    `json:"byte_start"`
+	ByteEnd   int    `json:"byte_end"`
+	Depth     int    `json:"depth"`
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	flags := flag.NewFlagSet("gotoken", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configPath := flags.String("config", "", "`file` with the tokenizer config (.json, .yaml or .yml)")
+	policy := flags.String("policy", defaultPolicy, "tokenization `policy`")
+	languages := flags.String("languages", "", "`languages` as name=Script+Script pairs separated by commas")
+	separators := flags.String("separators", "", "additional separator `runes`")
+	nonWord := flags.Bool("nonword", false, "split tokens on every non-word rune")
+	words := flags.Bool("words", false, "split tokens at Unicode word boundaries")
+	normalization := flags.String("normalize", "", "`forms` separated by commas: nfc, nfkc, casefold, strip_diacritics")
+	caseBoundaries := flags.Bool("case", false, "split letter blocks on case transitions (camelCase)")
+	code := flags.Bool("code", false, "tokenize program code: identifiers and operators")
+	graphemes := flags.Bool("graphemes", false, "split blocks by grapheme clusters instead of runes")
+	format := flags.String("format", "text", "output `format`: text, tsv or jsonl")
+	blocks := flags.Bool("blocks", false, "print block breakdowns of tokens")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	config := &gotoken.Config{}
+	if *configPath != "" {
+		var err error
+		if config, err = loadConfig(*configPath); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+	set := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	var err error
+	if set["policy"] || *configPath == "" {
+		if config.Policy, err = parsePolicy(*policy); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 2
+		}
+	}
+	if set["languages"] {
+		config.Languages = parseLanguages(*languages)
+	}
+	if set["separators"] {
+		config.Separators.Runes = *separators
+	}
+	if set["nonword"] {
+		config.Separators.NonWord = *nonWord
+	}
+	if set["words"] {
+		config.Separators.Words = *words
+	}
+	if set["normalize"] {
+		config.Normalization = splitList(*normalization)
+	}
+	if set["case"] {
+		config.CaseBoundaries = *caseBoundaries
+	}
+	if set["code"] {
+		config.Code = *code
+	}
+	if set["graphemes"] {
+		config.Graphemes = *graphemes
+	}
+	if *format != "text" && *format != "tsv" && *format != "jsonl" {
+		fmt.Fprintf(stderr, "gotoken: unknown format %q\n", *format)
+		return 2
+	}
+
+	st, err := config.NewTokenizer()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+
+	p := &printer{st: st, w: stdout, format: *format, blocks: *blocks}
+	if *format == "tsv" && !*blocks {
+		fmt.Fprintln(stdout, "file\tbyte_start\tbyte_end\ttoken\tlanguage\tbase\tdepth")
+	}
+	if flags.NArg() == 0 {
+		if err := p.print("", stdin); err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		return 0
+	}
+	for _, path := range flags.Args() {
+		file, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		err = p.print(path, file)
+		file.Close()
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+	}
+	return 0
+}
+
+func loadConfig(path string) (*gotoken.Config, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	switch filepath.Ext(path) {
+	case ".json":
+		return gotoken.LoadConfigJSON(file)
+	case ".yaml", ".yml":
+		return gotoken.LoadConfigYAML(file)
+	}
+	return nil, fmt.Errorf("gotoken: unknown config format of %s", path)
+}
+
+// parsePolicy parses a policy given as a type followed by parameters.
+func parsePolicy(spec string) (gotoken.PolicyConfig, error) {
+	var p gotoken.PolicyConfig
+	i := strings.IndexByte(spec, ':')
+	if i < 0 {
+		return p, fmt.Errorf("gotoken: policy %q has no parameters", spec)
+	}
+	p.Type = spec[:i]
+	params := splitList(spec[i+1:])
+
+	if p.Type == "step" {
+		for _, param := range params {
+			pair := strings.SplitN(param, "=", 2)
+			if len(pair) != 2 {
+				return p, fmt.Errorf("gotoken: step %q is not length=depth", param)
+			}
+			var step gotoken.PolicyStepPoint
+			var err error
+			if step.Length, err = strconv.Atoi(pair[0]); err != nil {
+				return p, fmt.Errorf("gotoken: step %q: %v", param, err)
+			}
+			if step.Depth, err = strconv.Atoi(pair[1]); err != nil {
+				return p, fmt.Errorf("gotoken: step %q: %v", param, err)
+			}
+			p.Steps = append(p.Steps, step)
+		}
+		return p, nil
+	}
+
+	var fields []*int
+	switch p.Type {
+	case "depth":
+		fields = []*int{&p.MaxLength, &p.MaxDepth, &p.MinLength, &p.MinDepth}
+	case "count":
+		fields = []*int{&p.Max}
+	case "log":
+		if len(params) != 3 {
+			return p, fmt.Errorf("gotoken: policy %q needs 3 parameters", p.Type)
+		}
+		scale, err := strconv.ParseFloat(params[2], 64)
+		if err != nil {
+			return p, fmt.Errorf("gotoken: policy %q: %v", p.Type, err)
+		}
+		p.Scale = scale
+		params = params[:2]
+		fields = []*int{&p.MaxDepth, &p.MinDepth}
+	case "blocks":
+		fields = []*int{&p.MaxBlocks, &p.MinDepth}
+	default:
+		return p, fmt.Errorf("gotoken: unknown policy type %q", p.Type)
+	}
+	if len(params) != len(fields) {
+		return p, fmt.Errorf("gotoken: policy %q needs %d parameters", p.Type, len(fields))
+	}
+	for index, param := range params {
+		value, err := strconv.Atoi(param)
+		if err != nil {
+			return p, fmt.Errorf("gotoken: policy %q: %v", p.Type, err)
+		}
+		*fields[index] = value
+	}
+	return p, nil
+}
+
+// parseLanguages parses name=Script+Script pairs. Scripts are checked by the config.
+func parseLanguages(spec string) []gotoken.LanguageConfig {
+	var languages []gotoken.LanguageConfig
+	for _, entry := range splitList(spec) {
+		name, scripts := entry, entry
+		if i := strings.IndexByte(entry, '='); i >= 0 {
+			name, scripts = entry[:i], entry[i+1:]
+		}
+		languages = append(languages, gotoken.LanguageConfig{Name: name, Scripts: strings.Split(scripts, "+")})
+	}
+	return languages
+}
+
+func splitList(s string) []string {
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// printer writes subtokens of sources in the chosen format.
+type printer struct {
+	st     *gotoken.SmartToken
+	w      io.Writer
+	format string
+	blocks bool
+}
+
+func (p *printer) print(file string, r io.Reader) error {
+	encoder := json.NewEncoder(p.w)
+	encoder.SetEscapeHTML(false)
+	var err error
+	readErr := p.st.TokenizeReaderOccurrences(r, func(offset int, token string, occurrences []gotoken.SmartTokenOccurrence) {
+		if err != nil {
+			return
+		}
+		if p.blocks {
+			err = p.printBlocks(offset, token)
+			return
+		}
+		for _, o := range occurrences {
+			language := p.languageName(o.Info.DetectedLanguage)
+			base := baseOf(token, offset, o)
+			switch p.format {
+			case "text":
+				_, err = fmt.Fprintf(p.w, "%s\t%s\t%s\n", o.Token, language, base)
+			case "tsv":
+				_, err = fmt.Fprintf(p.w, "%s\t%d\t%d\t%s\t%s\t%s\t%d\n", file, o.ByteStart, o.ByteEnd, o.Token, language, base, o.Depth)
+			case "jsonl":
+				err = encoder.Encode(record{
+					File:      file,
+					Token:     o.Token,
+					Language:  language,
+					Base:      base,
+					ByteStart: o.ByteStart,
+					ByteEnd:   o.ByteEnd,
+					Depth:     o.Depth,
+				})
+			}
+			if err != nil {
+				return
+			}
+		}
+	})
+	if readErr != nil {
+		return readErr
+	}
+	return err
+}
+
+// printBlocks prints the token with its blocks, the depth chosen by the policy
+// and all its subtokens with language decisions.
+func (p *printer) printBlocks(offset int, token string) error {
+	explanation := p.st.Explain(token)
+	if _, err := fmt.Fprintf(p.w, "token %q [%d, %d) depth %d\n", explanation.Token, offset, offset+len(token), explanation.Depth); err != nil {
+		return err
+	}
+	for _, b := range explanation.Blocks {
+		fmt.Fprintf(p.w, "  block %q [%d, %d) %v %s\n", explanation.Token[b.Start:b.End], b.Start, b.End, b.Class, p.languageName(b.Language))
+	}
+	for _, s := range explanation.Subtokens {
+		base := s.Subtoken[s.Info.DetectedBase[0]:s.Info.DetectedBase[1]]
+		fmt.Fprintf(p.w, "  subtoken %q blocks [%d, %d) %s base %q %v", s.Subtoken, s.Blocks[0], s.Blocks[1], p.languageName(s.Info.DetectedLanguage), base, s.Decision)
+		if s.Skipped {
+			fmt.Fprint(p.w, " skipped")
+		}
+		fmt.Fprintln(p.w)
+	}
+	return nil
+}
+
+func (p *printer) languageName(l gotoken.Language) string {
+	if l == gotoken.UnknownLanguage {
+		return "-"
+	}
+	if name := p.st.LanguageName(l); name != "" {
+		return name
+	}
+	return strconv.Itoa(int(l))
+}
+
+// baseOf returns the detected base of the occurrence in the token which starts
+// at the given offset of the source.
+func baseOf(token string, offset int, o gotoken.SmartTokenOccurrence) string {
+	left, right := o.ByteStart+o.Info.DetectedBase[0], o.ByteStart+o.Info.DetectedBase[1]
+	if left < o.ByteStart || right > o.ByteEnd || left > right {
+		return ""
+	}
+	return token[left-offset : right-offset]
+}
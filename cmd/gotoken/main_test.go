@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rvncerr/gotoken"
+	"github.com/stretchr/testify/assert"
+)
+
+func runString(args []string, stdin string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRunFormats(t *testing.T) {
+	ga := assert.New(t)
+
+	code, stdout, _ := runString([]string{"-languages", "en=Latin,ru=Cyrillic"}, "mail.ru-сервисы")
+	ga.Equal(0, code)
+	ga.Contains(stdout, "mail.ru\ten\tmail.ru\n")
+	ga.Contains(stdout, "mail.ru-сервисы\tru\tсервисы\n")
+
+	code, stdout, _ = runString([]string{"-languages", "Latin", "-format", "tsv"}, "a b")
+	ga.Equal(0, code)
+	ga.Equal("file\tbyte_start\tbyte_end\ttoken\tlanguage\tbase\tdepth\n\t0\t1\ta\tLatin\ta\t1\n\t2\t3\tb\tLatin\tb\t1\n", stdout)
+
+	code, stdout, _ = runString([]string{"-languages", "en=Latin", "-format", "jsonl", "-normalize", "casefold"}, "Hi")
+	ga.Equal(0, code)
+	ga.Equal(`{"token":"hi","language":"en","base":"Hi","byte_start":0,"byte_end":2,"depth":1}`+"\n", stdout)
+}
+
+func TestRunBlocks(t *testing.T) {
+	ga := assert.New(t)
+
+	code, stdout, _ := runString([]string{"-blocks", "-languages", "en=Latin"}, "123abc")
+	ga.Equal(0, code)
+	ga.Equal(`token "123abc" [0, 6) depth 10
+  block "123" [0, 3) Digit -
+  block "abc" [3, 6) Letter en
+  subtoken "123" blocks [0, 1) - base "" NoLetters
+  subtoken "123abc" blocks [0, 2) en base "abc" Single
+  subtoken "abc" blocks [1, 2) en base "abc" Single
+`, stdout)
+}
+
+func TestRunConfig(t *testing.T) {
+	ga := assert.New(t)
+	dir, err := ioutil.TempDir("", "gotoken")
+	ga.NoError(err)
+	defer os.RemoveAll(dir)
+
+	config := filepath.Join(dir, "config.yaml")
+	ga.NoError(ioutil.WriteFile(config, []byte("policy: {type: count, max: 3}\nlanguages: [{name: ru, scripts: [Cyrillic]}]\n"), 0644))
+	input := filepath.Join(dir, "input.txt")
+	ga.NoError(ioutil.WriteFile(input, []byte("да-нет"), 0644))
+
+	code, stdout, _ := runString([]string{"-config", config, input}, "")
+	ga.Equal(0, code)
+	ga.Equal("да\tru\tда\n-\t-\t\nнет\tru\tнет\n", stdout)
+
+	// Flags override the config.
+	code, stdout, _ = runString([]string{"-config", config, "-policy", "blocks:3,1", input}, "")
+	ga.Equal(0, code)
+	ga.Contains(stdout, "да-нет\tru\tда-нет\n")
+}
+
+func TestRunErrors(t *testing.T) {
+	ga := assert.New(t)
+
+	for _, args := range [][]string{
+		{"-policy", "depth"},
+		{"-policy", "depth:1,2,3"},
+		{"-policy", "step:4"},
+		{"-policy", "sqrt:4"},
+		{"-format", "xml"},
+		{"-undefined"},
+	} {
+		code, _, stderr := runString(args, "")
+		ga.Equal(2, code, "%v", args)
+		ga.NotEmpty(stderr, "%v", args)
+	}
+
+	code, _, stderr := runString([]string{"-languages", "en=Latn"}, "")
+	ga.Equal(1, code)
+	ga.Contains(stderr, "languages[0].scripts[0]")
+
+	code, _, _ = runString([]string{"/nonexistent/input.txt"}, "")
+	ga.Equal(1, code)
+}
+
+func TestParsePolicy(t *testing.T) {
+	ga := assert.New(t)
+
+	p, err := parsePolicy("step:4=8, 10=4")
+	ga.NoError(err)
+	ga.Equal(gotoken.PolicyConfig{Type: "step", Steps: []gotoken.PolicyStepPoint{{Length: 4, Depth: 8}, {Length: 10, Depth: 4}}}, p)
+
+	p, err = parsePolicy("log:10,2,1.5")
+	ga.NoError(err)
+	ga.Equal(gotoken.PolicyConfig{Type: "log", MaxDepth: 10, MinDepth: 2, Scale: 1.5}, p)
+}
@@ -0,0 +1,179 @@
+package gotoken
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is a declarative description of a tokenizer.
+type Config struct {
+	Policy         PolicyConfig     `json:"policy" yaml:"policy"`
+	Languages      []LanguageConfig `json:"languages" yaml:"languages"`
+	Separators     SeparatorConfig  `json:"separators" yaml:"separators"`
+	Normalization  []string         `json:"normalization" yaml:"normalization"`     // "nfc", "nfkc", "casefold", "strip_diacritics".
+	CaseBoundaries bool             `json:"case_boundaries" yaml:"case_boundaries"` // See SetCaseBoundaries.
+	Code           bool             `json:"code" yaml:"code"`                       // See SetCodeMode.
+	Graphemes      bool             `json:"graphemes" yaml:"graphemes"`             // See SetGraphemeClusters.
+}
+
+// PolicyConfig describes the policy of a tokenizer.
+type PolicyConfig struct {
+	Type      string            `json:"type" yaml:"type"` // "depth", "count", "step", "log" or "blocks".
+	MaxLength int               `json:"max_length" yaml:"max_length"`
+	MaxDepth  int               `json:"max_depth" yaml:"max_depth"`
+	MinLength int               `json:"min_length" yaml:"min_length"`
+	MinDepth  int               `json:"min_depth" yaml:"min_depth"`
+	Max       int               `json:"max" yaml:"max"`               // Subtoken budget of the count policy.
+	Steps     []PolicyStepPoint `json:"steps" yaml:"steps"`           // Points of the step policy.
+	Scale     float64           `json:"scale" yaml:"scale"`           // Scale of the log policy.
+	MaxBlocks int               `json:"max_blocks" yaml:"max_blocks"` // Block limit of the blocks policy.
+}
+
+// LanguageConfig describes a language by names of Unicode scripts (see unicode.Scripts).
+type LanguageConfig struct {
+	Name    string   `json:"name" yaml:"name"`
+	Scripts []string `json:"scripts" yaml:"scripts"`
+}
+
+// SeparatorConfig describes token separators. White space always separates tokens.
+type SeparatorConfig struct {
+	NonWord bool   `json:"nonword" yaml:"nonword"` // Split on everything SeparatorNonWordRune splits on.
+	Runes   string `json:"runes" yaml:"runes"`     // Split on these runes too.
+	Words   bool   `json:"words" yaml:"words"`     // Split at Unicode word boundaries, see SetWordBoundaries.
+}
+
+// ConfigError points at the invalid field of a config.
+type ConfigError struct {
+	Field   string
+	Message string
+}
+
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("gotoken: config: %s: %s", e.Field, e.Message)
+}
+
+var normalizations = map[string]Normalization{
+	"nfc":              NormalizeNFC,
+	"nfkc":             NormalizeNFKC,
+	"casefold":         NormalizeCaseFold,
+	"strip_diacritics": NormalizeStripDiacritics,
+}
+
+// LoadConfigJSON reads a config in JSON. Unknown fields are errors.
+func LoadConfigJSON(r io.Reader) (*Config, error) {
+	decoder := json.NewDecoder(r)
+	decoder.DisallowUnknownFields()
+	var c Config
+	if err := decoder.Decode(&c); err != nil {
+		return nil, fmt.Errorf("gotoken: config: %v", err)
+	}
+	return &c, nil
+}
+
+// LoadConfigYAML reads a config in YAML. Unknown fields are errors.
+func LoadConfigYAML(r io.Reader) (*Config, error) {
+	decoder := yaml.NewDecoder(r)
+	decoder.KnownFields(true)
+	var c Config
+	if err := decoder.Decode(&c); err != nil {
+		return nil, fmt.Errorf("gotoken: config: %v", err)
+	}
+	return &c, nil
+}
+
+// NewTokenizer validates the config and builds a tokenizer. Errors are of type *ConfigError.
+func (c *Config) NewTokenizer() (*SmartToken, error) {
+	policy, err := c.Policy.newPolicy()
+	if err != nil {
+		return nil, err
+	}
+	st := &SmartToken{policy: policy}
+
+	for i, l := range c.Languages {
+		if l.Name == "" {
+			return nil, &ConfigError{fmt.Sprintf("languages[%d].name", i), "empty name"}
+		}
+		if _, ok := st.LookupLanguage(l.Name); ok {
+			return nil, &ConfigError{fmt.Sprintf("languages[%d].name", i), fmt.Sprintf("duplicate language %q", l.Name)}
+		}
+		if len(l.Scripts) == 0 {
+			return nil, &ConfigError{fmt.Sprintf("languages[%d].scripts", i), "no scripts"}
+		}
+		tables := make([]*unicode.RangeTable, len(l.Scripts))
+		for j, script := range l.Scripts {
+			table, ok := unicode.Scripts[script]
+			if !ok {
+				return nil, &ConfigError{fmt.Sprintf("languages[%d].scripts[%d]", i, j), fmt.Sprintf("unknown script %q", script)}
+			}
+			tables[j] = table
+		}
+		st.AddLanguage(l.Name, tables...)
+	}
+
+	if c.Separators.NonWord || c.Separators.Runes != "" {
+		separators := []SeparatorFunc{SeparatorRunes(c.Separators.Runes)}
+		if c.Separators.NonWord {
+			separators = append(separators, SeparatorNonWordRune)
+		}
+		st.SetSeparator(SeparatorAny(separators...))
+	}
+
+	var normalization Normalization
+	for i, name := range c.Normalization {
+		n, ok := normalizations[name]
+		if !ok {
+			return nil, &ConfigError{fmt.Sprintf("normalization[%d]", i), fmt.Sprintf("unknown normalization %q", name)}
+		}
+		normalization |= n
+	}
+	st.SetNormalization(normalization)
+	st.SetCaseBoundaries(c.CaseBoundaries)
+	st.SetCodeMode(c.Code)
+	st.SetWordBoundaries(c.Separators.Words)
+	st.SetGraphemeClusters(c.Graphemes)
+	return st, nil
+}
+
+func (p PolicyConfig) newPolicy() (SmartTokenPolicy, error) {
+	var policy SmartTokenPolicy
+	var err error
+	switch p.Type {
+	case "depth":
+		policy, err = NewPolicyDepth(p.MaxLength, p.MaxDepth, p.MinLength, p.MinDepth)
+	case "count":
+		policy, err = NewPolicyCount(p.Max)
+	case "step":
+		policy, err = NewPolicyStep(p.Steps)
+	case "log":
+		policy, err = NewPolicyLog(p.MaxDepth, p.MinDepth, p.Scale)
+	case "blocks":
+		policy, err = NewPolicyBlocks(p.MaxBlocks, p.MinDepth)
+	case "":
+		return nil, &ConfigError{"policy.type", "missing policy type"}
+	default:
+		return nil, &ConfigError{"policy.type", fmt.Sprintf("unknown policy type %q", p.Type)}
+	}
+	if e, ok := err.(*PolicyError); ok {
+		return nil, &ConfigError{"policy." + configField(e.Param), e.Message}
+	}
+	return policy, err
+}
+
+// configField converts the name of a policy constructor parameter to the path
+// of the config field: "points[1].maxLength" becomes "steps[1].max_length".
+func configField(param string) string {
+	var field strings.Builder
+	for _, r := range strings.Replace(param, "points", "steps", 1) {
+		if unicode.IsUpper(r) {
+			field.WriteByte('_')
+			r = unicode.ToLower(r)
+		}
+		field.WriteRune(r)
+	}
+	return field.String()
+}
@@ -0,0 +1,85 @@
+package gotoken
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testConfigJSON = `{
+	"policy": {"type": "depth", "max_length": 10, "max_depth": 10, "min_length": 18, "min_depth": 2},
+	"languages": [
+		{"name": "en", "scripts": ["Latin"]},
+		{"name": "ru", "scripts": ["Cyrillic"]}
+	],
+	"separators": {"runes": ","},
+	"normalization": ["nfc", "casefold"]
+}`
+
+const testConfigYAML = `
+policy:
+  type: depth
+  max_length: 10
+  max_depth: 10
+  min_length: 18
+  min_depth: 2
+languages:
+  - name: en
+    scripts: [Latin]
+  - name: ru
+    scripts: [Cyrillic]
+separators:
+  runes: ","
+normalization: [nfc, casefold]
+`
+
+func TestConfig(t *testing.T) {
+	assert := assert.New(t)
+	expected := map[string]SmartTokenInfo{
+		"hello":  SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 5}},
+		"привет": SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 12}},
+	}
+
+	for _, load := range []func() (*Config, error){
+		func() (*Config, error) { return LoadConfigJSON(strings.NewReader(testConfigJSON)) },
+		func() (*Config, error) { return LoadConfigYAML(strings.NewReader(testConfigYAML)) },
+	} {
+		c, err := load()
+		assert.NoError(err)
+		st, err := c.NewTokenizer()
+		assert.NoError(err)
+		ru, ok := st.LookupLanguage("ru")
+		assert.True(ok)
+		assert.Equal(Language(1), ru)
+		assert.Equal(expected, st.TokenizeString("Hello,ПРИВЕТ"))
+	}
+}
+
+func TestConfigErrors(t *testing.T) {
+	assert := assert.New(t)
+
+	_, err := LoadConfigJSON(strings.NewReader(`{"policy": {"kind": "depth"}}`))
+	assert.Error(err, "unknown field")
+	_, err = LoadConfigYAML(strings.NewReader("policy:\n  kind: depth\n"))
+	assert.Error(err, "unknown field")
+
+	testSet := map[string]Config{
+		"policy.type":             Config{},
+		"policy.min_length":       Config{Policy: PolicyConfig{Type: "depth", MaxLength: 10, MaxDepth: 10, MinLength: 10, MinDepth: 2}},
+		"policy.max":              Config{Policy: PolicyConfig{Type: "count"}},
+		"languages[0].scripts":    Config{Policy: PolicyConfig{Type: "count", Max: 10}, Languages: []LanguageConfig{LanguageConfig{Name: "en"}}},
+		"languages[1].name":       Config{Policy: PolicyConfig{Type: "count", Max: 10}, Languages: []LanguageConfig{LanguageConfig{Name: "en", Scripts: []string{"Latin"}}, LanguageConfig{Name: "en", Scripts: []string{"Greek"}}}},
+		"languages[0].scripts[1]": Config{Policy: PolicyConfig{Type: "count", Max: 10}, Languages: []LanguageConfig{LanguageConfig{Name: "en", Scripts: []string{"Latin", "Latn"}}}},
+		"policy.steps[1].length":  Config{Policy: PolicyConfig{Type: "step", Steps: []PolicyStepPoint{PolicyStepPoint{Length: 1, Depth: 4}, PolicyStepPoint{Length: 1, Depth: 2}}}},
+		"policy.scale":            Config{Policy: PolicyConfig{Type: "log", MaxDepth: 4, MinDepth: 1}},
+		"policy.max_blocks":       Config{Policy: PolicyConfig{Type: "blocks", MinDepth: 1}},
+		"normalization[0]":        Config{Policy: PolicyConfig{Type: "count", Max: 10}, Normalization: []string{"nfd"}},
+	}
+	for field, c := range testSet {
+		_, err := c.NewTokenizer()
+		if assert.IsType(&ConfigError{}, err, field) {
+			assert.Equal(field, err.(*ConfigError).Field)
+		}
+	}
+}
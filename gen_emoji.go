@@ -0,0 +1,142 @@
+//go:build ignore
+// +build ignore
+
+// This program generates emoji_table.go from the Unicode emoji data. Run it
+// with "go generate". A local copy of the data can be given with -data.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const dataURL = "https://unicode.org/Public/15.0.0/ucd/emoji/emoji-data.txt"
+
+// properties are the emoji properties whose runes form the table. Regional
+// indicators are taken from unicode.Regional_Indicator at run time.
+var properties = map[string]bool{
+	"Extended_Pictographic": true,
+	"Emoji_Modifier":        true,
+}
+
+func main() {
+	data := flag.String("data", "", "local copy of emoji-data.txt")
+	output := flag.String("output", "emoji_table.go", "output file")
+	flag.Parse()
+
+	var r io.Reader
+	source := dataURL
+	if *data != "" {
+		file, err := os.Open(*data)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer file.Close()
+		r = file
+	} else {
+		response, err := http.Get(dataURL)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer response.Body.Close()
+		if response.StatusCode != http.StatusOK {
+			log.Fatalf("%s: %s", dataURL, response.Status)
+		}
+		r = response.Body
+	}
+
+	ranges, err := parse(r)
+	if err != nil {
+		log.Fatal(err)
+	}
+	code, err := generate(source, ranges)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := ioutil.WriteFile(*output, code, 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// parse returns sorted and merged ranges of runes with the chosen properties.
+func parse(r io.Reader) ([][2]rune, error) {
+	var ranges [][2]rune
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if index := strings.IndexByte(line, '#'); index >= 0 {
+			line = line[:index]
+		}
+		fields := strings.Split(line, ";")
+		if len(fields) != 2 || !properties[strings.TrimSpace(fields[1])] {
+			continue
+		}
+		bounds := strings.Split(strings.TrimSpace(fields[0]), "..")
+		lo, err := strconv.ParseUint(bounds[0], 16, 32)
+		if err != nil {
+			return nil, err
+		}
+		hi := lo
+		if len(bounds) == 2 {
+			if hi, err = strconv.ParseUint(bounds[1], 16, 32); err != nil {
+				return nil, err
+			}
+		}
+		ranges = append(ranges, [2]rune{rune(lo), rune(hi)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+
+	var merged [][2]rune
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && r[0] <= merged[n-1][1]+1 {
+			if r[1] > merged[n-1][1] {
+				merged[n-1][1] = r[1]
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged, nil
+}
+
+func generate(source string, ranges [][2]rune) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Code generated by gen_emoji.go from %s. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&b, "package gotoken\n\nimport (\n\t\"unicode\"\n)\n\n")
+	fmt.Fprintf(&b, "// emojiTable holds runes with the Extended_Pictographic and Emoji_Modifier properties.\n")
+	fmt.Fprintf(&b, "var emojiTable = &unicode.RangeTable{\n\tR16: []unicode.Range16{\n")
+	latin := 0
+	for _, r := range ranges {
+		if r[1] <= 0xffff {
+			fmt.Fprintf(&b, "\t\t{Lo: 0x%04x, Hi: 0x%04x, Stride: 1},\n", r[0], r[1])
+			if r[1] <= unicode.MaxLatin1 {
+				latin++
+			}
+		}
+	}
+	fmt.Fprintf(&b, "\t},\n\tR32: []unicode.Range32{\n")
+	for _, r := range ranges {
+		if r[0] > 0xffff {
+			fmt.Fprintf(&b, "\t\t{Lo: 0x%x, Hi: 0x%x, Stride: 1},\n", r[0], r[1])
+		} else if r[1] > 0xffff {
+			return nil, fmt.Errorf("range %x..%x crosses the BMP", r[0], r[1])
+		}
+	}
+	fmt.Fprintf(&b, "\t},\n\tLatinOffset: %d,\n}\n", latin)
+	return format.Source(b.Bytes())
+}
@@ -0,0 +1,40 @@
+package gotoken
+
+import (
+	"fmt"
+)
+
+// PolicyBlocks depends on the number of blocks in a token. Tokens of at most
+// maxBlocks blocks produce every combination of their blocks, longer tokens
+// get minDepth. Without the shape of a token every rune is assumed to be a
+// separate block.
+type PolicyBlocks struct {
+	maxBlocks int
+	minDepth  int
+}
+
+// NewPolicyBlocks validates the parameters and creates the policy. Both of them
+// must be positive.
+func NewPolicyBlocks(maxBlocks int, minDepth int) (SmartTokenPolicy, error) {
+	switch {
+	case maxBlocks < 1:
+		return nil, &PolicyError{"blocks", "maxBlocks", fmt.Sprintf("%d is not positive", maxBlocks)}
+	case minDepth < 1:
+		return nil, &PolicyError{"blocks", "minDepth", fmt.Sprintf("%d is not positive", minDepth)}
+	}
+	return &PolicyBlocks{
+		maxBlocks: maxBlocks,
+		minDepth:  minDepth,
+	}, nil
+}
+
+func (p *PolicyBlocks) GetDepth(length int) int {
+	return p.GetShapeDepth(SmartTokenShape{Length: length, Blocks: length})
+}
+
+func (p *PolicyBlocks) GetShapeDepth(shape SmartTokenShape) int {
+	if shape.Blocks <= p.maxBlocks {
+		return shape.Blocks
+	}
+	return p.minDepth
+}
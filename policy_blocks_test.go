@@ -0,0 +1,37 @@
+package gotoken
+
+import (
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDepthBlocks(t *testing.T) {
+	ga := assert.New(t)
+	st, err := NewPolicyBlocks(4, 2)
+	ga.NoError(err)
+
+	ga.Equal(3, st.GetDepth(3), "blocks policy short token")
+	ga.Equal(2, st.GetDepth(5), "blocks policy long token")
+	ga.Equal(4, st.(SmartTokenShapePolicy).GetShapeDepth(SmartTokenShape{Length: 100, Blocks: 4}), "blocks policy long word")
+
+	_, err = NewPolicyBlocks(0, 2)
+	ga.Error(err)
+	_, err = NewPolicyBlocks(4, 0)
+	ga.Error(err)
+}
+
+func TestTokenizerBlocks(t *testing.T) {
+	ga := assert.New(t)
+	policy, err := NewPolicyBlocks(3, 1)
+	ga.NoError(err)
+	st, err := NewTokenizer(policy)
+	ga.NoError(err)
+	st.AddRangeTable(unicode.Latin)
+
+	// Three blocks regardless of the length: every combination.
+	ga.Len(st.TokenizeString("abcdefghijklmnop-qrstuvwxyz"), 6)
+	// Five blocks: single blocks only.
+	ga.Len(st.TokenizeString("a-b-c"), 4)
+}
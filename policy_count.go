@@ -1,11 +1,48 @@
 package gotoken
 
+import (
+	"fmt"
+)
+
+// PolicyCount limits the number of subtokens produced from a single token.
+// The depth is chosen by the number of blocks in the token, so that every
+// combination of up to depth blocks fits into the budget. If even single
+// blocks do not fit, only the first max of them are emitted.
 type PolicyCount int
 
-func NewPolicyCount(max int) SmartTokenPolicy {
-	return nil
+// NewPolicyCount creates the policy. The budget must be positive.
+func NewPolicyCount(max int) (SmartTokenPolicy, error) {
+	if max < 1 {
+		return nil, &PolicyError{"count", "max", fmt.Sprintf("%d is not positive", max)}
+	}
+	return PolicyCount(max), nil
 }
 
+// GetDepth is like GetShapeDepth for a token whose every rune is a separate block.
 func (p PolicyCount) GetDepth(length int) int {
-	panic("PolicyCount is not implemented yet")
+	return p.GetShapeDepth(SmartTokenShape{Length: length, Blocks: length})
+}
+
+// GetShapeDepth returns the largest depth which keeps the number of subtokens
+// of the token under the budget. The depth is never less than 1.
+func (p PolicyCount) GetShapeDepth(shape SmartTokenShape) int {
+	depth := 1
+	for depth < shape.Blocks && countSubtokens(shape.Blocks, depth+1) <= int(p) {
+		depth++
+	}
+	return depth
+}
+
+// GetLimit returns the budget.
+func (p PolicyCount) GetLimit() int {
+	return int(p)
+}
+
+// countSubtokens returns the number of subtokens of a token which consists of
+// the given number of blocks.
+func countSubtokens(blocks int, depth int) int {
+	if depth > blocks {
+		depth = blocks
+	}
+	return depth*blocks - depth*(depth-1)/2
 }
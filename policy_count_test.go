@@ -0,0 +1,79 @@
+package gotoken
+
+import (
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDepthCount(t *testing.T) {
+	const budget = 20
+
+	ga := assert.New(t)
+	st := PolicyCount(budget)
+
+	for i := 1; i <= budget; i++ {
+		depth := st.GetDepth(i)
+		ga.True(depth >= 1 && depth <= i, "count policy depth range")
+		ga.True(countSubtokens(i, depth) <= budget, "count policy budget")
+		if depth < i {
+			ga.True(countSubtokens(i, depth+1) > budget, "count policy maximal depth")
+		}
+	}
+
+	memory := st.GetDepth(budget)
+	for i := budget + 1; i <= budget+20; i++ {
+		ga.Equal(st.GetDepth(i), 1, "count policy long tokens")
+		ga.True(st.GetDepth(i) <= memory, "count policy monotony")
+	}
+}
+
+func TestNewPolicyCount(t *testing.T) {
+	ga := assert.New(t)
+	for _, max := range []int{0, -1} {
+		_, err := NewPolicyCount(max)
+		ga.Error(err, "budget %v", max)
+	}
+	_, err := NewPolicyCount(1)
+	ga.NoError(err)
+}
+
+func TestTokenizerCount(t *testing.T) {
+	const budget = 10
+
+	ga := assert.New(t)
+	policy, err := NewPolicyCount(budget)
+	ga.NoError(err)
+	st, err := NewTokenizer(policy)
+	ga.NoError(err)
+	st.AddRangeTable(unicode.Latin)
+
+	// Every emission counts, duplicates included.
+	for _, input := range []string{"a", "a.b", "a.b.c", "a.b.c.d", "aaa...bbb", "a-b-c-d-e-f", "a-b-c-d-e-f-g-h-i-j-k"} {
+		occurrences := st.TokenizeStringOccurrences(input)
+		ga.True(len(occurrences) <= budget, "count policy tokenization of '%v' -> %v", input, occurrences)
+	}
+	ga.Len(st.TokenizeStringOccurrences("a-b-c-d-e-f-g-h-i-j-k"), budget, "single blocks are cut by the budget")
+
+	explanation := st.Explain("a-b-c-d-e-f-g-h-i-j-k")
+	ga.Len(explanation.Subtokens, 21)
+	ga.False(explanation.Subtokens[budget-1].Skipped)
+	ga.True(explanation.Subtokens[budget].Skipped)
+}
+
+func TestTokenizerCountShape(t *testing.T) {
+	ga := assert.New(t)
+	policy, err := NewPolicyCount(6)
+	ga.NoError(err)
+	st, err := NewTokenizer(policy)
+	ga.NoError(err)
+	st.AddRangeTable(unicode.Latin)
+
+	// The depth depends on blocks rather than runes: three blocks fit into the
+	// budget with every combination.
+	ga.Len(st.TokenizeStringOccurrences("hello-world"), 6)
+	ga.Equal(3, st.Explain("hello-world").Depth)
+	ga.Equal(3, PolicyCount(6).GetDepth(3))
+	ga.Equal(1, PolicyCount(6).GetDepth(11))
+}
@@ -1,5 +1,14 @@
 package gotoken
 
+import (
+	"fmt"
+)
+
+// PolicyDepth decreases the depth linearly with the token length between two
+// anchor points. Tokens up to maxLength runes long (short tokens) get maxDepth,
+// tokens of minLength runes or longer get minDepth, the depth of tokens in
+// between is interpolated. That is, maxLength and maxDepth describe the anchor
+// of the maximal depth rather than the maximal length.
 type PolicyDepth struct {
 	maxLength int
 	maxDepth  int
@@ -7,17 +16,37 @@ type PolicyDepth struct {
 	minDepth  int
 }
 
-func NewPolicyDepth(maxLength int, maxDepth int, minLength int, minDepth int) SmartTokenPolicy {
+// NewPolicyDepth validates the anchor points and creates the policy. Lengths
+// must satisfy 0 < maxLength < minLength and depths 1 <= minDepth <= maxDepth.
+func NewPolicyDepth(maxLength int, maxDepth int, minLength int, minDepth int) (SmartTokenPolicy, error) {
+	switch {
+	case maxLength < 1:
+		return nil, &PolicyError{"depth", "maxLength", fmt.Sprintf("%d is not positive", maxLength)}
+	case minLength <= maxLength:
+		return nil, &PolicyError{"depth", "minLength", fmt.Sprintf("%d is not greater than maxLength %d", minLength, maxLength)}
+	case minDepth < 1:
+		return nil, &PolicyError{"depth", "minDepth", fmt.Sprintf("%d is not positive", minDepth)}
+	case maxDepth < minDepth:
+		return nil, &PolicyError{"depth", "maxDepth", fmt.Sprintf("%d is less than minDepth %d", maxDepth, minDepth)}
+	}
 	return &PolicyDepth{
 		maxLength: maxLength,
 		maxDepth:  maxDepth,
 		minLength: minLength,
 		minDepth:  minDepth,
+	}, nil
+}
+
+// MustPolicyDepth is like NewPolicyDepth but panics on invalid anchor points.
+func MustPolicyDepth(maxLength int, maxDepth int, minLength int, minDepth int) SmartTokenPolicy {
+	policy, err := NewPolicyDepth(maxLength, maxDepth, minLength, minDepth)
+	if err != nil {
+		panic(err)
 	}
+	return policy
 }
 
 func (p *PolicyDepth) GetDepth(length int) int {
-	// TODO: It seems min & max conditoins are reversed???
 	if length <= p.maxLength {
 		return p.maxDepth
 	} else if length >= p.minLength {
@@ -13,7 +13,8 @@ func TestGetDepth(t *testing.T) {
 	const rightY = 10
 
 	ga := assert.New(t)
-	st := NewPolicyDepth(leftX, leftY, rightX, rightY)
+	st, err := NewPolicyDepth(leftX, leftY, rightX, rightY)
+	ga.NoError(err)
 
 	for i := 1; i <= leftX; i++ {
 		ga.Equal(st.GetDepth(i), leftY, "depth policy left part")
@@ -29,3 +30,53 @@ func TestGetDepth(t *testing.T) {
 		ga.Equal(st.GetDepth(i), rightY, "depth policy right part")
 	}
 }
+
+func TestNewPolicyDepth(t *testing.T) {
+	ga := assert.New(t)
+
+	for _, anchors := range [][4]int{
+		{0, 10, 18, 2},  // Non-positive maxLength.
+		{10, 10, 10, 2}, // Equal lengths: interpolation divides by zero.
+		{18, 10, 10, 2}, // Reversed lengths.
+		{10, 10, 18, 0}, // Non-positive minDepth.
+		{10, 2, 18, 10}, // Reversed depths.
+	} {
+		_, err := NewPolicyDepth(anchors[0], anchors[1], anchors[2], anchors[3])
+		ga.Error(err, "depth policy %v", anchors)
+		ga.Panics(func() { MustPolicyDepth(anchors[0], anchors[1], anchors[2], anchors[3]) }, "depth policy %v", anchors)
+		ga.Panics(func() { NewDepthTokenizer(anchors[0], anchors[1], anchors[2], anchors[3]) }, "depth policy %v", anchors)
+	}
+
+	policy, err := NewPolicyDepth(10, 2, 18, 2)
+	ga.NoError(err, "constant depth")
+	ga.Equal(2, policy.GetDepth(14), "constant depth")
+}
+
+func TestNilPolicy(t *testing.T) {
+	ga := assert.New(t)
+
+	_, err := NewTokenizer(nil)
+	ga.Equal(ErrNilPolicy, err)
+
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	ga.Equal(ErrNilPolicy, st.SetPolicy(nil))
+	ga.NotEmpty(st.TokenizeString("hello"), "previous policy is kept")
+	ga.Equal(ErrNilPolicy, st.SetPolicy((*PolicyDepth)(nil)), "typed nil")
+	_, err = NewTokenizer((*PolicyStep)(nil))
+	ga.Equal(ErrNilPolicy, err, "typed nil")
+
+	st, err = NewTokenizer(PolicyCount(10))
+	ga.NoError(err)
+	ga.NotEmpty(st.TokenizeString("hello"))
+}
+
+func TestZeroTokenizer(t *testing.T) {
+	ga := assert.New(t)
+	var st SmartToken
+
+	ga.Equal(map[string]SmartTokenInfo{
+		"a": SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 1}},
+		".": SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+		"b": SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 1}},
+	}, st.TokenizeString("a.b"), "zero tokenizer emits single blocks")
+}
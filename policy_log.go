@@ -0,0 +1,43 @@
+package gotoken
+
+import (
+	"fmt"
+	"math"
+)
+
+// PolicyLog decreases the depth logarithmically with the token length: a token
+// of length runes gets maxDepth - scale*log2(length) but not less than minDepth.
+type PolicyLog struct {
+	maxDepth int
+	minDepth int
+	scale    float64
+}
+
+// NewPolicyLog validates the parameters and creates the policy. Depths must
+// satisfy 1 <= minDepth <= maxDepth and scale must be positive.
+func NewPolicyLog(maxDepth int, minDepth int, scale float64) (SmartTokenPolicy, error) {
+	switch {
+	case minDepth < 1:
+		return nil, &PolicyError{"log", "minDepth", fmt.Sprintf("%d is not positive", minDepth)}
+	case maxDepth < minDepth:
+		return nil, &PolicyError{"log", "maxDepth", fmt.Sprintf("%d is less than minDepth %d", maxDepth, minDepth)}
+	case !(scale > 0) || math.IsInf(scale, 1):
+		return nil, &PolicyError{"log", "scale", fmt.Sprintf("%v is not positive", scale)}
+	}
+	return &PolicyLog{
+		maxDepth: maxDepth,
+		minDepth: minDepth,
+		scale:    scale,
+	}, nil
+}
+
+func (p *PolicyLog) GetDepth(length int) int {
+	if length <= 1 {
+		return p.maxDepth
+	}
+	depth := float64(p.maxDepth) - p.scale*math.Log2(float64(length))
+	if depth < float64(p.minDepth) {
+		return p.minDepth
+	}
+	return int(depth)
+}
@@ -0,0 +1,31 @@
+package gotoken
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDepthLog(t *testing.T) {
+	ga := assert.New(t)
+	st, err := NewPolicyLog(10, 2, 2)
+	ga.NoError(err)
+
+	for length, depth := range map[int]int{1: 10, 2: 8, 4: 6, 8: 4, 16: 2, 1000: 2} {
+		ga.Equal(depth, st.GetDepth(length), "log policy length %v", length)
+	}
+
+	memory := st.GetDepth(1)
+	for i := 2; i <= 100; i++ {
+		ga.True(st.GetDepth(i) <= memory, "log policy monotony")
+		memory = st.GetDepth(i)
+	}
+
+	for _, scale := range []float64{0, -1, math.NaN(), math.Inf(1)} {
+		_, err := NewPolicyLog(10, 2, scale)
+		ga.Error(err, "log policy scale %v", scale)
+	}
+	_, err = NewPolicyLog(2, 10, 1)
+	ga.Error(err, "log policy reversed depths")
+}
@@ -0,0 +1,45 @@
+package gotoken
+
+import (
+	"fmt"
+)
+
+// PolicyStepPoint is a step of PolicyStep: tokens of Length runes or longer get Depth.
+type PolicyStepPoint struct {
+	Length int `json:"length" yaml:"length"`
+	Depth  int `json:"depth" yaml:"depth"`
+}
+
+// PolicyStep is a piecewise constant policy. A token gets the depth of the last
+// point not longer than the token, tokens shorter than the first point get its depth.
+type PolicyStep struct {
+	points []PolicyStepPoint
+}
+
+// NewPolicyStep validates the points and creates the policy. Lengths of points
+// must increase and depths must be positive.
+func NewPolicyStep(points []PolicyStepPoint) (SmartTokenPolicy, error) {
+	if len(points) == 0 {
+		return nil, &PolicyError{"step", "points", "are empty"}
+	}
+	for index, point := range points {
+		if point.Depth < 1 {
+			return nil, &PolicyError{"step", fmt.Sprintf("points[%d].depth", index), fmt.Sprintf("%d is not positive", point.Depth)}
+		}
+		if index > 0 && point.Length <= points[index-1].Length {
+			return nil, &PolicyError{"step", fmt.Sprintf("points[%d].length", index), fmt.Sprintf("%d does not increase", point.Length)}
+		}
+	}
+	return &PolicyStep{points: append([]PolicyStepPoint(nil), points...)}, nil
+}
+
+func (p *PolicyStep) GetDepth(length int) int {
+	depth := p.points[0].Depth
+	for _, point := range p.points[1:] {
+		if length < point.Length {
+			break
+		}
+		depth = point.Depth
+	}
+	return depth
+}
@@ -0,0 +1,26 @@
+package gotoken
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDepthStep(t *testing.T) {
+	ga := assert.New(t)
+	st, err := NewPolicyStep([]PolicyStepPoint{{Length: 4, Depth: 8}, {Length: 10, Depth: 4}, {Length: 20, Depth: 2}})
+	ga.NoError(err)
+
+	for length, depth := range map[int]int{1: 8, 4: 8, 9: 8, 10: 4, 19: 4, 20: 2, 100: 2} {
+		ga.Equal(depth, st.GetDepth(length), "step policy length %v", length)
+	}
+
+	for _, points := range [][]PolicyStepPoint{
+		nil,
+		{{Length: 4, Depth: 0}},
+		{{Length: 4, Depth: 8}, {Length: 4, Depth: 4}},
+	} {
+		_, err := NewPolicyStep(points)
+		ga.Error(err, "step policy %v", points)
+	}
+}
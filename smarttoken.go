@@ -1,13 +1,15 @@
 package gotoken
 
 import (
+	"errors"
+	"reflect"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"unicode"
 	"unicode/utf8"
-
-	"github.com/rvncerr/gocontainers"
 )
 
-//go:generate stringer -tsype=RuneClass -output=rune_class_string_gen.go
 type RuneClass int
 
 const (
@@ -16,97 +18,137 @@ const (
 	Digit
 	Punct
 	Other
+	Mark      // Combining marks. They attach to the preceding block.
+	Symbol    // Math, modifier and other symbols: "+", "°".
+	Currency  // Currency symbols: "$", "€".
+	Emoji     // Pictographs, emoji modifiers and regional indicators: "😀", "☀", "©".
+	Connector // Connector punctuation: "_".
 )
 
+func (c RuneClass) String() string {
+	switch c {
+	case Undef:
+		return "Undef"
+	case Letter:
+		return "Letter"
+	case Digit:
+		return "Digit"
+	case Punct:
+		return "Punct"
+	case Other:
+		return "Other"
+	case Mark:
+		return "Mark"
+	case Symbol:
+		return "Symbol"
+	case Currency:
+		return "Currency"
+	case Emoji:
+		return "Emoji"
+	case Connector:
+		return "Connector"
+	}
+	return "RuneClass(" + strconv.Itoa(int(c)) + ")"
+}
+
 // SmartTokenInfo provides basic information about the token.
 type SmartTokenInfo struct {
-	DetectedLanguage int
+	DetectedLanguage Language
 	DetectedBase     [2]int
+	Stem             string // Stem of the base if a stemmer is set for the language.
 }
 
-// SmartToken is a tokenizer for SmartToken algorithm.
+// SmartToken is a tokenizer for SmartToken algorithm. A configured tokenizer
+// is safe for concurrent use, but it must not be reconfigured while in use.
+// A zero SmartToken has no policy and emits single blocks only.
 type SmartToken struct {
-	rangeTableList          []*unicode.RangeTable
-	previousRangeTableIndex int
-	currentRangeTableIndex  int
-	previousRuneClass       RuneClass
-	currentRuneClass        RuneClass
-	policy                  SmartTokenPolicy
+	languages      []language
+	policy         SmartTokenPolicy
+	separator      SeparatorFunc
+	normalization  Normalization
+	caseBoundaries bool
+	code           bool
+	classifier     RuneClassifier
+	graphemes      bool
+	wordBoundaries bool
+	dictionaries   map[Language]*Dictionary
+	ngrams         NGrams
+	stemmers       map[Language]Stemmer
+	stopWords      map[Language]StopWords
+	table          atomic.Value // *runeTable, nil until built.
+	tableMutex     sync.Mutex   // Serializes building of the table.
 }
 
-func (st *SmartToken) detectBase(bs []interface{}, left int, right int) [2]int {
-	return [2]int{bs[left].(int) - bs[0].(int), bs[right].(int) - bs[0].(int)}
+// blockScanner splits a token into blocks rune by rune.
+type blockScanner struct {
+	table             *runeTable
+	previousLanguage  Language
+	currentLanguage   Language
+	previousRuneClass RuneClass
+	currentRuneClass  RuneClass
+	splits            []bool // Byte offset -> Is word boundary.
+	code              bool   // Digits after letters are a suffix of the letter block.
+	graphemes         bool   // Units are grapheme clusters instead of runes.
+	suffix            bool   // The last rune belongs to a digit suffix.
 }
 
-func (st *SmartToken) detectLanguage(bs []interface{}, rc []interface{}, rt []interface{}) (int, [2]int) {
-	// fmt.Printf("%v %v\n", rc, rt)
-	length := len(rc)
-	switch {
-	case length == 1:
-		if rc[0] == Letter {
-			return rt[0].(int), st.detectBase(bs, 0, 1) // "hello", "привет"
-		}
-		return -1, [2]int{0, 0} // "123"
-	case length == 2:
-		switch {
-		case rc[0] == Letter && rc[1] == Letter:
-			return rt[1].(int), st.detectBase(bs, 0, 2) // "mailка"
-		case rc[0] == Letter && rc[1] != Letter:
-			return rt[0].(int), st.detectBase(bs, 0, 1) // "привет---"
-		case rc[0] != Letter && rc[1] == Letter:
-			return rt[1].(int), st.detectBase(bs, 1, 2) // "---привет"
-		default:
-			return -1, [2]int{0, 0} // "---123"
-		}
-	case length == 3:
-		switch {
-		case rc[0] == Letter && rc[1] == Letter && rc[2] == Letter:
-			return -1, [2]int{0, 0} // "mailприветhello"
-		case rc[0] == Letter && rc[1] == Letter && rc[2] != Letter:
-			return rt[1].(int), st.detectBase(bs, 0, 2) // "mailка---"
-		case rc[0] == Letter && rc[1] != Letter && rc[2] == Letter:
-			if rt[0] == rt[2] {
-				return rt[2].(int), st.detectBase(bs, 0, 3) // "карабас-барабас"
-			}
-			return rt[2].(int), st.detectBase(bs, 2, 3) // "css-стили"
-		case rc[0] != Letter && rc[1] == Letter && rc[2] == Letter:
-			return rt[2].(int), st.detectBase(bs, 2, 3) // "---mailка"
-		case rc[0] == Letter && rc[1] != Letter && rc[2] != Letter:
-			return rt[0].(int), st.detectBase(bs, 0, 1) // "привет---123"
-		case rc[0] != Letter && rc[1] == Letter && rc[2] != Letter:
-			return rt[1].(int), st.detectBase(bs, 1, 2) // "---привет---"
-		case rc[0] != Letter && rc[1] != Letter && rc[2] == Letter:
-			return rt[2].(int), st.detectBase(bs, 2, 3) // "123---привет"
-		default:
-			return -1, [2]int{0, 0} // "123---123"
-		}
-	default:
-		return -1, [2]int{0, 0}
+// NewDepthTokenizer creates a tokenizer with the depth policy. It panics if
+// the anchor points are invalid, see NewPolicyDepth.
+func NewDepthTokenizer(maxLength int, maxDepth int, minLength int, minDepth int) *SmartToken {
+	return &SmartToken{
+		policy: MustPolicyDepth(maxLength, maxDepth, minLength, minDepth),
 	}
 }
 
-func NewDepthTokenizer(maxLength int, maxDepth int, minLength int, minDepth int) *SmartToken {
-	policy := NewPolicyDepth(maxLength, maxDepth, minLength, minDepth)
-	return &SmartToken{
-		policy: policy,
+// NewTokenizer creates a tokenizer with the given policy.
+func NewTokenizer(p SmartTokenPolicy) (*SmartToken, error) {
+	st := &SmartToken{}
+	if err := st.SetPolicy(p); err != nil {
+		return nil, err
 	}
+	return st, nil
 }
 
-// AddRangeTable pushes new language into tokenizer.
+// AddRangeTable pushes new anonymous language into tokenizer.
 func (st *SmartToken) AddRangeTable(rt *unicode.RangeTable) {
-	st.rangeTableList = append(st.rangeTableList, rt)
+	st.AddLanguage("", rt)
 }
 
+// ErrNilPolicy is returned when a tokenizer is given no policy or a nil
+// pointer (map, function) as a policy.
+var ErrNilPolicy = errors.New("gotoken: nil policy")
+
 // SetPolicy tells tokenizer how to calculate token sizes
-func (st *SmartToken) SetPolicy(p SmartTokenPolicy) {
+func (st *SmartToken) SetPolicy(p SmartTokenPolicy) error {
+	if p == nil {
+		return ErrNilPolicy
+	}
+	switch v := reflect.ValueOf(p); v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Func, reflect.Slice, reflect.Chan, reflect.Interface:
+		if v.IsNil() {
+			return ErrNilPolicy
+		}
+	}
 	st.policy = p
+	return nil
 }
 
 // TokenizeString starts SmartToken tokenization process on a string.
 func (st *SmartToken) TokenizeString(source string) map[string]SmartTokenInfo {
 	tokens := make(map[string]SmartTokenInfo) // Token -> Info.
 	// distribution := make(map[int]int) // Depth -> Count(Token).
+	st.splitTokens(source, func(offset int, token string) {
+		st.processToken(token, func(subtoken string, left int, right int, depth int, info SmartTokenInfo) {
+			if depth > 0 {
+				tokens[subtoken] = info
+			}
+		})
+	})
+	return tokens
+}
 
+// splitTokens calls fn for every token of the source.
+func (st *SmartToken) splitTokens(source string, fn func(offset int, token string)) {
 	const stateSpace = 0
 	const stateToken = 1
 
@@ -115,135 +157,261 @@ func (st *SmartToken) TokenizeString(source string) map[string]SmartTokenInfo {
 	for index, r := range source {
 		switch state {
 		case stateSpace:
-			if !unicode.IsSpace(r) {
+			if !st.isSeparator(r) {
 				state = stateToken
 				offset = index
 			}
 			break
 		case stateToken:
-			if unicode.IsSpace(r) {
+			if st.isSeparator(r) {
 				state = stateSpace
-				st.getSubtokens(source[offset:index], tokens)
+				st.splitParts(source[offset:index], func(part int, token string) {
+					fn(offset+part, token)
+				})
 			}
 			break
 		}
 	}
 	if state == stateToken {
-		st.getSubtokens(source[offset:], tokens)
+		st.splitParts(source[offset:], func(part int, token string) {
+			fn(offset+part, token)
+		})
 	}
-	return tokens
 }
 
-func (st *SmartToken) getSubtokens(token string, tokens map[string]SmartTokenInfo) {
-	st.flush()
-	depth := st.policy.GetDepth(utf8.RuneCountInString(token)) + 1
-	blockSizeBuffer := gocontainers.NewCircularBuffer(depth)
-	runeClassBuffer := gocontainers.NewCircularBuffer(depth - 1)
-	rangeTableBuffer := gocontainers.NewCircularBuffer(depth - 1)
-
-	for index, r := range token {
-		if st.pushRune(r) {
-			blockSizeBuffer.PushBack(index)
-			if st.previousRuneClass != Undef {
-				runeClassBuffer.PushBack(st.previousRuneClass)
-				if st.previousRuneClass == Letter {
-					rangeTableBuffer.PushBack(st.previousRangeTableIndex)
-				} else {
-					rangeTableBuffer.PushBack(-1)
-				}
-			}
-			if blockSizeBuffer.Full() {
-				array := blockSizeBuffer.ToArray()
-				left := blockSizeBuffer.Front()
-				for depth, right := range array[1:] {
-					var info SmartTokenInfo
-					info.DetectedLanguage, info.DetectedBase =
-						st.detectLanguage(array, runeClassBuffer.ToArray()[0:depth+1], rangeTableBuffer.ToArray()[0:depth+1])
-					tokens[token[left.(int):right.(int)]] = info
-				}
-			}
+// processToken normalizes the token and calls emit for every subtoken of it.
+// Subtoken is passed as normalized text and byte offsets inside the original
+// token.
+func (st *SmartToken) processToken(token string, emit func(subtoken string, left int, right int, depth int, info SmartTokenInfo)) {
+	normalized, offsets := st.normalize(token)
+	if normalized == "" {
+		return
+	}
+
+	blocks := st.getBlocks(normalized)
+	stop := st.getStopBlocks(normalized, blocks)
+	limit := st.getLimit()
+	st.getSubtokens(normalized, blocks, st.getDepth(normalized, blocks), func(left int, right int, depth int, info SmartTokenInfo) {
+		if limit == 0 || stop.skip(left, right) {
+			return
+		}
+		if limit > 0 {
+			limit--
+		}
+		info.Stem = st.stem(normalized[left:right], info)
+		sourceLeft, sourceRight := offsets.span(left, right)
+		baseLeft, baseRight := offsets.span(left+info.DetectedBase[0], left+info.DetectedBase[1])
+		info.DetectedBase = [2]int{baseLeft - sourceLeft, baseRight - sourceLeft}
+		emit(normalized[left:right], sourceLeft, sourceRight, depth, info)
+	})
+	if st.ngrams.Max > 0 {
+		st.getNGrams(normalized, blocks, offsets, stop, emit)
+	}
+}
+
+// getSubtokens calls emit for every subtoken of the token of up to depth
+// blocks. Subtoken is passed as byte offsets inside the token and the number of
+// blocks it consists of. Subtokens are emitted by their first block and then by
+// length.
+func (st *SmartToken) getSubtokens(token string, blocks *tokenBlocks, depth int, emit func(left int, right int, depth int, info SmartTokenInfo)) {
+	bs, rc, rt := blocks.bs, blocks.rc, blocks.rt
+	for first := range rc {
+		for last := first + 1; last <= len(rc) && last-first <= depth; last++ {
+			var info SmartTokenInfo
+			info.DetectedLanguage, info.DetectedBase, _ = st.detectLanguage(token, bs[first:last+1], rc[first:last], rt[first:last])
+			emit(bs[first].(int), bs[last].(int), last-first, info)
 		}
 	}
+}
 
-	blockSizeBuffer.PushBack(len(token))
-	runeClassBuffer.PushBack(st.currentRuneClass)
-	if st.currentRuneClass == Letter {
-		rangeTableBuffer.PushBack(st.currentRangeTableIndex)
+// getLimit returns the maximal number of subtokens of a token, -1 for no limit.
+func (st *SmartToken) getLimit() int {
+	if policy, ok := st.policy.(SmartTokenLimitPolicy); ok {
+		if limit := policy.GetLimit(); limit >= 0 {
+			return limit
+		}
+	}
+	return -1
+}
+
+// getDepth asks the policy for the maximal number of blocks in a subtoken.
+func (st *SmartToken) getDepth(token string, blocks *tokenBlocks) int {
+	var depth int
+	if st.policy == nil {
+		return 1
+	}
+	if policy, ok := st.policy.(SmartTokenShapePolicy); ok {
+		depth = policy.GetShapeDepth(SmartTokenShape{
+			Length: utf8.RuneCountInString(token),
+			Blocks: len(blocks.rc),
+		})
 	} else {
-		rangeTableBuffer.PushBack(-1)
+		depth = st.policy.GetDepth(utf8.RuneCountInString(token))
 	}
+	if depth < 1 {
+		return 1
+	}
+	return depth
+}
 
-	// fmt.Printf("token = %v, bs = %v, rc = %v, rt = %v\n", token, blockSizeBuffer.ToArray(), runeClassBuffer.ToArray(), rangeTableBuffer.ToArray())
+// tokenBlocks keeps blocks of a token: block boundaries (one more than blocks),
+// rune classes and languages of the blocks. They are built once per token and
+// shared by the policy, stop words, subtokens and n-grams.
+type tokenBlocks struct {
+	bs []interface{}
+	rc []interface{}
+	rt []interface{}
+}
 
-	for !blockSizeBuffer.Empty() {
-		array := blockSizeBuffer.ToArray()
-		left := blockSizeBuffer.Front()
-		for depth, right := range array[1:] {
-			var info SmartTokenInfo
-			info.DetectedLanguage, info.DetectedBase =
-				st.detectLanguage(array, runeClassBuffer.ToArray()[0:depth+1], rangeTableBuffer.ToArray()[0:depth+1])
-			tokens[token[left.(int):right.(int)]] = info
+// getBlocks splits the non-empty token into blocks.
+func (st *SmartToken) getBlocks(token string) *tokenBlocks {
+	var bs, rc, rt []interface{}
+	sc := st.newScanner(token)
+	sc.scan(token, func(index int, split bool) {
+		if split {
+			bs = append(bs, index)
+			if sc.previousRuneClass != Undef {
+				rc = append(rc, sc.previousRuneClass)
+				if sc.previousRuneClass == Letter {
+					rt = append(rt, sc.previousLanguage)
+				} else {
+					rt = append(rt, UnknownLanguage)
+				}
+			}
 		}
-		blockSizeBuffer.PopFront()
-		runeClassBuffer.PopFront()
-		rangeTableBuffer.PopFront()
+	})
+	bs = append(bs, len(token))
+	rc = append(rc, sc.currentRuneClass)
+	if sc.currentRuneClass == Letter {
+		rt = append(rt, sc.currentLanguage)
+	} else {
+		rt = append(rt, UnknownLanguage)
+	}
+	return &tokenBlocks{bs: bs, rc: rc, rt: rt}
+}
+
+func (st *SmartToken) newScanner(token string) *blockScanner {
+	sc := &blockScanner{table: st.runeTable(), code: st.code, graphemes: st.graphemes}
+	sc.flush()
+	sc.splits = st.segment(token, sc.table)
+	if st.caseBoundaries {
+		sc.splits = caseSplits(token, sc.splits)
 	}
+	return sc
+}
+
+func (sc *blockScanner) flush() {
+	sc.previousRuneClass = Undef
+	sc.currentRuneClass = Undef
+	sc.previousLanguage = UnknownLanguage
+	sc.currentLanguage = UnknownLanguage
+	sc.suffix = false
 }
 
-func (st *SmartToken) flush() {
-	st.previousRuneClass = Undef
-	st.currentRuneClass = Undef
-	st.previousRangeTableIndex = -1
-	st.currentRangeTableIndex = -1
+// scan pushes every unit of the token (a rune or a grapheme cluster) and calls
+// fn telling whether a new block starts at the unit.
+func (sc *blockScanner) scan(token string, fn func(index int, split bool)) {
+	if !sc.graphemes {
+		for index, r := range token {
+			class, language := sc.table.lookup(r)
+			fn(index, sc.push(index, class, language))
+		}
+		return
+	}
+	forEachGrapheme(token, func(index int, cluster string) {
+		class, language := graphemeClass(cluster, sc.table)
+		fn(index, sc.push(index, class, language))
+	})
 }
 
 // very dirty!!!
-func (st *SmartToken) pushRune(r rune) bool {
+func (sc *blockScanner) push(index int, newRuneClass RuneClass, newLanguage Language) bool {
 	result := false
-	newRuneClass := st.getRuneClass(r)
+
+	if newRuneClass == Mark && sc.currentRuneClass != Undef {
+		return false
+	}
+
+	if sc.code {
+		if newRuneClass == Other || newRuneClass == Symbol || newRuneClass == Currency {
+			newRuneClass = Punct // Operators are single blocks: "!=".
+		}
+		if newRuneClass == Digit && (sc.suffix || sc.currentRuneClass == Letter) {
+			sc.suffix = true
+			return false
+		}
+		if sc.suffix {
+			sc.suffix = false
+			if newRuneClass == Letter && newLanguage == sc.currentLanguage {
+				sc.previousLanguage = sc.currentLanguage
+				sc.previousRuneClass = sc.currentRuneClass
+				return true
+			}
+		}
+	}
+
+	if sc.splits != nil && sc.splits[index] && newRuneClass == sc.currentRuneClass && newLanguage == sc.currentLanguage {
+		sc.previousLanguage = sc.currentLanguage
+		sc.previousRuneClass = sc.currentRuneClass
+		return true
+	}
 
 	if newRuneClass == Letter {
-		newRangeTableIndex := st.getTableIndex(r)
-		if newRangeTableIndex != st.currentRangeTableIndex {
-			st.previousRangeTableIndex = st.currentRangeTableIndex
-			st.currentRangeTableIndex = newRangeTableIndex
-			st.previousRuneClass = st.currentRuneClass
-			st.currentRuneClass = newRuneClass
+		if newLanguage != sc.currentLanguage {
+			sc.previousLanguage = sc.currentLanguage
+			sc.currentLanguage = newLanguage
+			sc.previousRuneClass = sc.currentRuneClass
+			sc.currentRuneClass = newRuneClass
 			result = true
-		} else if newRuneClass != st.currentRuneClass {
-			st.previousRangeTableIndex = st.currentRangeTableIndex
-			st.currentRangeTableIndex = -1
-			st.previousRuneClass = st.currentRuneClass
-			st.currentRuneClass = newRuneClass
+		} else if newRuneClass != sc.currentRuneClass {
+			sc.previousLanguage = sc.currentLanguage
+			sc.currentLanguage = UnknownLanguage
+			sc.previousRuneClass = sc.currentRuneClass
+			sc.currentRuneClass = newRuneClass
 			result = true
 		}
-	} else if newRuneClass != st.currentRuneClass {
-		st.previousRangeTableIndex = st.currentRangeTableIndex
-		st.currentRangeTableIndex = -1
-		st.previousRuneClass = st.currentRuneClass
-		st.currentRuneClass = newRuneClass
+	} else if newRuneClass != sc.currentRuneClass {
+		sc.previousLanguage = sc.currentLanguage
+		sc.currentLanguage = UnknownLanguage
+		sc.previousRuneClass = sc.currentRuneClass
+		sc.currentRuneClass = newRuneClass
 		result = true
 	}
 	return result
 }
 
+//go:generate go run gen_emoji.go
+
+// getRuneClass classifies the rune by its Unicode category. Pictographs
+// (Extended_Pictographic), emoji modifiers and regional indicators are Emoji
+// unless they are letters or digits: "‼" and "〽" are Emoji, "ℹ" is Letter.
 func (st *SmartToken) getRuneClass(r rune) RuneClass {
 	switch {
 	case unicode.IsLetter(r):
 		return Letter
 	case unicode.IsDigit(r):
 		return Digit
+	case unicode.IsMark(r):
+		return Mark
+	case unicode.Is(unicode.Pc, r):
+		return Connector
+	case unicode.In(r, emojiTable, unicode.Regional_Indicator):
+		return Emoji
 	case unicode.IsPunct(r):
 		return Punct
+	case unicode.Is(unicode.Sc, r):
+		return Currency
+	case unicode.IsSymbol(r):
+		return Symbol
 	}
 	return Other
 }
 
-func (st *SmartToken) getTableIndex(r rune) int {
-	for index, rangeTable := range st.rangeTableList {
-		if unicode.In(r, rangeTable) {
-			return index
+func (st *SmartToken) getLanguage(r rune) Language {
+	for index, language := range st.languages {
+		if language.contains(r) {
+			return Language(index)
 		}
 	}
-	return -1
+	return UnknownLanguage
 }
@@ -0,0 +1,39 @@
+package gotoken
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// SetCaseBoundaries tells tokenizer to split letter blocks on case transitions:
+// from lowercase to uppercase ("camel|Case") and from an uppercase run to
+// a capitalized word ("XML|Http"). Boundaries are found in the normalized
+// token, so NormalizeCaseFold erases them: use LowercaseFilter instead.
+func (st *SmartToken) SetCaseBoundaries(enabled bool) {
+	st.caseBoundaries = enabled
+}
+
+// caseSplits marks case boundaries of the token in splits, allocating them if needed.
+func caseSplits(token string, splits []bool) []bool {
+	previous, beforePrevious := utf8.RuneError, utf8.RuneError
+	previousIndex := 0
+	for index, r := range token {
+		split := -1
+		switch {
+		case unicode.IsLower(previous) && (unicode.IsUpper(r) || unicode.IsTitle(r)):
+			split = index
+		case unicode.IsUpper(previous) && unicode.IsTitle(r):
+			split = index
+		case unicode.IsUpper(beforePrevious) && unicode.IsUpper(previous) && unicode.IsLower(r):
+			split = previousIndex
+		}
+		if split >= 0 {
+			if splits == nil {
+				splits = make([]bool, len(token))
+			}
+			splits[split] = true
+		}
+		beforePrevious, previous, previousIndex = previous, r, index
+	}
+	return splits
+}
@@ -0,0 +1,75 @@
+package gotoken
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCaseBoundaries(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+	st.AddRangeTable(unicode.Cyrillic)
+	st.SetCaseBoundaries(true)
+
+	testSet := []tokenizerTestSet{
+		tokenizerTestSet{
+			input: "camelCase",
+			output: map[string]SmartTokenInfo{
+				"camel":     SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 5}},
+				"Case":      SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 4}},
+				"camelCase": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 9}},
+			},
+		},
+		tokenizerTestSet{
+			input: "XMLHttp",
+			output: map[string]SmartTokenInfo{
+				"XML":     SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 3}},
+				"Http":    SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 4}},
+				"XMLHttp": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 7}},
+			},
+		},
+		tokenizerTestSet{ // Case and language boundaries coexist.
+			input: "getЗначение",
+			output: map[string]SmartTokenInfo{
+				"get":         SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 3}},
+				"Значение":    SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 16}},
+				"getЗначение": SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 19}},
+			},
+		},
+		tokenizerTestSet{ // Digits are blocks of their own anyway.
+			input: "parseHTML5Doc",
+			output: map[string]SmartTokenInfo{
+				"parse":         SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 5}},
+				"HTML":          SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 4}},
+				"5":             SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"Doc":           SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 3}},
+				"parseHTML":     SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 9}},
+				"HTML5":         SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 4}},
+				"5Doc":          SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{1, 4}},
+				"parseHTML5":    SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 9}},
+				"HTML5Doc":      SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 8}},
+				"parseHTML5Doc": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 13}},
+			},
+		},
+		tokenizerTestSet{ // Single words are not split.
+			input: "Hello WORLD",
+			output: map[string]SmartTokenInfo{
+				"Hello": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 5}},
+				"WORLD": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 5}},
+			},
+		},
+	}
+
+	for _, test := range testSet {
+		result := st.TokenizeString(test.input)
+		assert.True(reflect.DeepEqual(result, test.output), fmt.Sprintf("wrong tokenization of '%v' -> %v", test.input, result))
+	}
+
+	st.SetCaseBoundaries(false)
+	assert.Len(st.TokenizeString("camelCase"), 1, "case boundaries are optional")
+}
@@ -0,0 +1,44 @@
+package gotoken
+
+// RuneClassifier maps a rune to its class and language. The class must be one
+// of Letter, Digit, Punct, Other, Mark, Symbol, Currency, Emoji and Connector:
+// Undef and unknown classes are treated as Other. The language matters for
+// letters only. Results are cached, so a classifier must always return the
+// same result for the same rune.
+type RuneClassifier interface {
+	Classify(r rune) (RuneClass, Language)
+}
+
+// RuneClassifierFunc is an adapter to use ordinary functions as classifiers.
+type RuneClassifierFunc func(r rune) (RuneClass, Language)
+
+// Classify calls f(r).
+func (f RuneClassifierFunc) Classify(r rune) (RuneClass, Language) {
+	return f(r)
+}
+
+// DefaultRuneClassifier returns the classifier used unless another one is set:
+// classes come from Unicode categories and languages of letters come from the
+// registered languages. Custom classifiers can fall back to it.
+func (st *SmartToken) DefaultRuneClassifier() RuneClassifier {
+	return RuneClassifierFunc(st.classifySlow)
+}
+
+// SetRuneClassifier tells tokenizer how to classify runes. Nil restores the
+// default classifier.
+func (st *SmartToken) SetRuneClassifier(c RuneClassifier) {
+	st.classifier = c
+	st.resetRuneTable()
+}
+
+// classify returns the class and the language of the rune bypassing the rune table.
+func (st *SmartToken) classify(r rune) (RuneClass, Language) {
+	if st.classifier == nil {
+		return st.classifySlow(r)
+	}
+	class, language := st.classifier.Classify(r)
+	if class < Letter || class > Connector {
+		class = Other
+	}
+	return class, language
+}
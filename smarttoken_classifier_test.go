@@ -0,0 +1,107 @@
+package gotoken
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRuneClassifier(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	latin := st.AddLanguage("en", unicode.Latin)
+	st.AddLanguage("el", unicode.Greek)
+
+	// Handles and hashtags are words, Greek letters are math symbols.
+	def := st.DefaultRuneClassifier()
+	st.SetRuneClassifier(RuneClassifierFunc(func(r rune) (RuneClass, Language) {
+		switch {
+		case r == '@' || r == '#':
+			return Letter, latin
+		case unicode.Is(unicode.Greek, r):
+			return Symbol, UnknownLanguage
+		}
+		return def.Classify(r)
+	}))
+
+	testSet := []tokenizerTestSet{
+		tokenizerTestSet{
+			input: "@gopher #golang",
+			output: map[string]SmartTokenInfo{
+				"@gopher": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 7}},
+				"#golang": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 7}},
+			},
+		},
+		tokenizerTestSet{
+			input: "2πr",
+			output: map[string]SmartTokenInfo{
+				"2":   SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"π":   SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"r":   SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 1}},
+				"2π":  SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"πr":  SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{2, 3}},
+				"2πr": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{3, 4}},
+			},
+		},
+	}
+	for _, test := range testSet {
+		result := st.TokenizeString(test.input)
+		assert.True(reflect.DeepEqual(result, test.output), fmt.Sprintf("wrong tokenization of '%v' -> %v", test.input, result))
+	}
+
+	st.SetRuneClassifier(nil)
+	info, _ := st.DetectLanguage("π")
+	assert.Equal(Language(1), info.DetectedLanguage, "default classifier is restored")
+}
+
+func TestRuneClassifierTable(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+
+	// Languages which do not fit into the rune table are classified on the fly.
+	st.SetRuneClassifier(RuneClassifierFunc(func(r rune) (RuneClass, Language) {
+		return Letter, Language(r)
+	}))
+	assert.Nil(st.runeTable().pages)
+	class, language := st.runeTable().lookup('ж')
+	assert.Equal(Letter, class)
+	assert.Equal(Language('ж'), language)
+}
+
+func TestRuneClassifierClasses(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	latin := st.AddLanguage("en", unicode.Latin)
+	st.SetStemmer(latin, EnglishStemmer)
+
+	// Invalid classes are treated as Other and keep the rune table.
+	def := st.DefaultRuneClassifier()
+	st.SetRuneClassifier(RuneClassifierFunc(func(r rune) (RuneClass, Language) {
+		switch r {
+		case '*':
+			return Undef, UnknownLanguage
+		case '~':
+			return RuneClass(42), UnknownLanguage
+		case '#':
+			return Letter, latin
+		}
+		return def.Classify(r)
+	}))
+	assert.NotNil(st.runeTable().pages)
+	for _, r := range "*~" {
+		class, _ := st.runeTable().lookup(r)
+		assert.Equal(Other, class)
+	}
+	result := st.TokenizeString("a*~b")
+	assert.Contains(result, "*~")
+	assert.Contains(result, "a*~b")
+
+	// Stemming and language detection follow the classifier.
+	result = st.TokenizeString("#running")
+	assert.Equal("#run", result["#running"].Stem)
+	info, _ := st.DetectLanguage("#go")
+	assert.Equal([2]int{0, 3}, info.DetectedBase)
+}
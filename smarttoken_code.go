@@ -0,0 +1,56 @@
+package gotoken
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SetCodeMode tells tokenizer to treat the source as program code. Tokens are
+// split on white space, brackets, commas, semicolons and quotes. Every token
+// is then split into identifiers and operators: identifiers consist of
+// letters, digits and connectors ("_"), and also contain "." and "-" between
+// such runes, so dotted paths ("os.path.join") and kebab-case stay single
+// tokens whose parts are combined by the policy. Runs of other punctuation and
+// symbols are operators (":=", "->", "&&"). Digits after letters are a suffix
+// of the letter block ("utf8", "md5|sum"). Combine with SetCaseBoundaries for
+// camelCase.
+func (st *SmartToken) SetCodeMode(enabled bool) {
+	st.code = enabled
+}
+
+// codeSeparator tells whether the rune separates tokens of code.
+func codeSeparator(r rune) bool {
+	return unicode.IsSpace(r) || unicode.In(r, unicode.Ps, unicode.Pe, unicode.Pi, unicode.Pf) || strings.ContainsRune(",;\"'`", r)
+}
+
+// codeWord tells whether the rune can be a part of an identifier.
+func codeWord(r rune) bool {
+	return unicode.In(r, unicode.L, unicode.M, unicode.Nd, unicode.Pc)
+}
+
+// codeJoiner tells whether the rune joins identifier parts when it stands between them.
+func codeJoiner(r rune) bool {
+	return r == '.' || r == '-'
+}
+
+// splitCode calls fn for every identifier and operator of the token.
+func splitCode(token string, fn func(offset int, part string)) {
+	start := 0
+	previousWord := false
+	for index, r := range token {
+		word := codeWord(r)
+		if !word && codeJoiner(r) && previousWord {
+			next, _ := utf8.DecodeRuneInString(token[index+utf8.RuneLen(r):])
+			word = codeWord(next)
+		}
+		if index > 0 && word != previousWord {
+			fn(start, token[start:index])
+			start = index
+		}
+		previousWord = word
+	}
+	if start < len(token) {
+		fn(start, token[start:])
+	}
+}
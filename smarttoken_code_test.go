@@ -0,0 +1,73 @@
+package gotoken
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitCode(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.SetCodeMode(true)
+
+	testSet := map[string][]string{
+		"x:=os.path.join(a,b);": {"x", ":=", "os.path.join", "a", "b"},
+		"foo-bar->baz":          {"foo-bar", "->", "baz"},
+		"i++ a&&b args...":      {"i", "++", "a", "&&", "b", "args", "..."},
+		"__init__ 3.14":         {"__init__", "3.14"},
+		`"utf8" [md5sum]`:       {"utf8", "md5sum"},
+		"a.":                    {"a", "."},
+	}
+	for input, expected := range testSet {
+		var result []string
+		st.splitTokens(input, func(offset int, token string) {
+			assert.Equal(token, input[offset:offset+len(token)], "offset of '%v' in '%v'", token, input)
+			result = append(result, token)
+		})
+		assert.Equal(expected, result, "wrong split of '%v'", input)
+	}
+}
+
+func TestCodeMode(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(20, 10, 30, 2)
+	st.AddRangeTable(unicode.Latin)
+	st.SetCodeMode(true)
+	st.SetCaseBoundaries(true)
+
+	testSet := []struct {
+		input  string
+		output []string
+	}{
+		{"parse_http_request", []string{"_", "_http", "_http_", "_http_request", "_request", "http", "http_", "http_request",
+			"parse", "parse_", "parse_http", "parse_http_", "parse_http_request", "request"}},
+		{"md5sum", []string{"md5", "md5sum", "sum"}},
+		{"utf8.Valid(b)", []string{".", ".Valid", "Valid", "b", "utf8", "utf8.", "utf8.Valid"}},
+		{"base64Encode", []string{"Encode", "base64", "base64Encode"}},
+		{"x != 42", []string{"!=", "42", "x"}},
+	}
+	for _, test := range testSet {
+		result := st.TokenizeString(test.input)
+		var subtokens []string
+		for subtoken := range result {
+			subtokens = append(subtokens, subtoken)
+		}
+		sort.Strings(subtokens)
+		assert.Equal(test.output, subtokens, "wrong tokenization of '%v'", test.input)
+	}
+
+	info := st.TokenizeString("md5sum")["md5"]
+	assert.Equal(SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 3}}, info, "digit suffix belongs to the word")
+
+	var streamed []string
+	assert.NoError(st.TokenizeReader(strings.NewReader("a:=b"), func(subtoken string, info SmartTokenInfo) {
+		streamed = append(streamed, subtoken)
+	}))
+	assert.True(reflect.DeepEqual(streamed, []string{"a", ":=", "b"}), fmt.Sprintf("wrong streamed tokenization -> %v", streamed))
+}
@@ -0,0 +1,171 @@
+package gotoken
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Dictionary is a list of words with their frequencies. It is used to segment
+// languages written without spaces between words.
+type Dictionary struct {
+	frequencies  map[string]float64
+	total        float64
+	minFrequency float64
+	maxLength    int // The longest word in runes.
+}
+
+// NewDictionary creates an empty dictionary.
+func NewDictionary() *Dictionary {
+	return &Dictionary{
+		frequencies: make(map[string]float64),
+	}
+}
+
+// LoadDictionary reads a dictionary from a text file. Every line holds a word
+// optionally followed by its frequency (1 by default). Empty lines and lines
+// starting with '#' are skipped.
+func LoadDictionary(r io.Reader) (*Dictionary, error) {
+	d := NewDictionary()
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		frequency := 1.0
+		switch len(fields) {
+		case 1:
+		case 2:
+			var err error
+			frequency, err = strconv.ParseFloat(fields[1], 64)
+			if err != nil || frequency <= 0 {
+				return nil, fmt.Errorf("gotoken: dictionary line %d: invalid frequency %q", line, fields[1])
+			}
+		default:
+			return nil, fmt.Errorf("gotoken: dictionary line %d: too many fields", line)
+		}
+		d.Add(fields[0], frequency)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Add pushes new word into dictionary. Frequencies of the same word are summed.
+func (d *Dictionary) Add(word string, frequency float64) {
+	if word == "" || frequency <= 0 {
+		return
+	}
+	d.frequencies[word] += frequency
+	d.total += frequency
+	if d.minFrequency == 0 || frequency < d.minFrequency {
+		d.minFrequency = frequency
+	}
+	if length := utf8.RuneCountInString(word); length > d.maxLength {
+		d.maxLength = length
+	}
+}
+
+// Segment splits the text into the most probable sequence of words and
+// returns byte offsets of boundaries between them. Runes which are not
+// covered by dictionary words become single-rune words.
+func (d *Dictionary) Segment(text string) []int {
+	var offsets []int // Rune -> Byte offset.
+	for index := range text {
+		offsets = append(offsets, index)
+	}
+	offsets = append(offsets, len(text))
+	length := len(offsets) - 1
+	if length <= 1 || d.total == 0 {
+		return nil
+	}
+
+	// Unigram model: a word costs -log(probability). Unknown runes are less
+	// probable than any known word.
+	logTotal := math.Log(d.total)
+	unknownCost := logTotal - math.Log(d.minFrequency/10)
+
+	costs := make([]float64, length+1) // Rune -> Cost of the best segmentation of the prefix.
+	previous := make([]int, length+1)  // Rune -> Start of the last word.
+	for i := 1; i <= length; i++ {
+		costs[i] = costs[i-1] + unknownCost
+		previous[i] = i - 1
+		for j := i - d.maxLength; j < i; j++ {
+			if j < 0 {
+				continue
+			}
+			frequency, ok := d.frequencies[text[offsets[j]:offsets[i]]]
+			if !ok {
+				continue
+			}
+			if cost := costs[j] + logTotal - math.Log(frequency); cost < costs[i] || (cost == costs[i] && j < previous[i]) {
+				costs[i] = cost
+				previous[i] = j
+			}
+		}
+	}
+
+	var boundaries []int
+	for i := previous[length]; i > 0; i = previous[i] {
+		boundaries = append(boundaries, offsets[i])
+	}
+	for left, right := 0, len(boundaries)-1; left < right; left, right = left+1, right-1 {
+		boundaries[left], boundaries[right] = boundaries[right], boundaries[left]
+	}
+	return boundaries
+}
+
+// SetDictionary tells tokenizer to split letter blocks of the language into
+// dictionary words. Nil dictionary disables segmentation.
+func (st *SmartToken) SetDictionary(l Language, d *Dictionary) {
+	if d == nil {
+		delete(st.dictionaries, l)
+		return
+	}
+	if st.dictionaries == nil {
+		st.dictionaries = make(map[Language]*Dictionary)
+	}
+	st.dictionaries[l] = d
+}
+
+// segment marks byte offsets of the token where dictionary words begin.
+// It returns nil if there are no dictionaries.
+func (st *SmartToken) segment(token string, table *runeTable) []bool {
+	if len(st.dictionaries) == 0 {
+		return nil
+	}
+	splits := make([]bool, len(token))
+	start := 0
+	runLanguage := UnknownLanguage
+	flush := func(end int) {
+		if d, ok := st.dictionaries[runLanguage]; ok && runLanguage != UnknownLanguage {
+			for _, boundary := range d.Segment(token[start:end]) {
+				splits[start+boundary] = true
+			}
+		}
+	}
+	for index, r := range token {
+		class, language := table.lookup(r)
+		if class == Mark && index > 0 {
+			continue
+		}
+		if class != Letter {
+			language = UnknownLanguage
+		}
+		if language != runLanguage {
+			flush(index)
+			start = index
+			runLanguage = language
+		}
+	}
+	flush(len(token))
+	return splits
+}
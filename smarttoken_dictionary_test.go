@@ -0,0 +1,78 @@
+package gotoken
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDictionary = `
+# word frequency
+我 10
+爱 5
+北京 8
+天安门 3
+天 2
+安 1
+门 2
+`
+
+func TestLoadDictionary(t *testing.T) {
+	assert := assert.New(t)
+
+	d, err := LoadDictionary(strings.NewReader(testDictionary))
+	assert.NoError(err)
+	assert.Equal([]int{3, 6, 12}, d.Segment("我爱北京天安门"))
+	assert.Equal([]int{6}, d.Segment("北京门"))
+	assert.Equal([]int{3, 6}, d.Segment("我你爱"), "unknown runes")
+	assert.Nil(d.Segment("我"))
+
+	_, err = LoadDictionary(strings.NewReader("我 10\n爱 many\n"))
+	assert.EqualError(err, `gotoken: dictionary line 2: invalid frequency "many"`)
+	_, err = LoadDictionary(strings.NewReader("我 1 2\n"))
+	assert.Error(err)
+}
+
+func TestTokenizerDictionary(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+	zh := st.AddLanguage("zh", unicode.Han)
+	d, err := LoadDictionary(strings.NewReader(testDictionary))
+	assert.NoError(err)
+	st.SetDictionary(zh, d)
+
+	testSet := []tokenizerTestSet{
+		tokenizerTestSet{
+			input: "我爱北京。",
+			output: map[string]SmartTokenInfo{
+				"我":     SmartTokenInfo{DetectedLanguage: zh, DetectedBase: [2]int{0, 3}},
+				"爱":     SmartTokenInfo{DetectedLanguage: zh, DetectedBase: [2]int{0, 3}},
+				"北京":    SmartTokenInfo{DetectedLanguage: zh, DetectedBase: [2]int{0, 6}},
+				"。":     SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"我爱":    SmartTokenInfo{DetectedLanguage: zh, DetectedBase: [2]int{0, 6}},
+				"爱北京":   SmartTokenInfo{DetectedLanguage: zh, DetectedBase: [2]int{0, 9}},
+				"北京。":   SmartTokenInfo{DetectedLanguage: zh, DetectedBase: [2]int{0, 6}},
+				"我爱北京":  SmartTokenInfo{DetectedLanguage: zh, DetectedBase: [2]int{0, 12}},
+				"爱北京。":  SmartTokenInfo{DetectedLanguage: zh, DetectedBase: [2]int{0, 9}},
+				"我爱北京。": SmartTokenInfo{DetectedLanguage: zh, DetectedBase: [2]int{0, 12}},
+			},
+		},
+		tokenizerTestSet{
+			input: "hello北京",
+			output: map[string]SmartTokenInfo{
+				"hello":   SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 5}},
+				"北京":      SmartTokenInfo{DetectedLanguage: zh, DetectedBase: [2]int{0, 6}},
+				"hello北京": SmartTokenInfo{DetectedLanguage: zh, DetectedBase: [2]int{0, 11}},
+			},
+		},
+	}
+	for _, test := range testSet {
+		result := st.TokenizeString(test.input)
+		assert.True(reflect.DeepEqual(result, test.output), fmt.Sprintf("wrong tokenization of '%v' -> %v", test.input, result))
+	}
+}
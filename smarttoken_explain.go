@@ -0,0 +1,81 @@
+package gotoken
+
+// SmartTokenBlock is a block of a token: a run of runes of the same class and language.
+type SmartTokenBlock struct {
+	Start    int // Byte offset of the block in the token.
+	End      int // Exclusive end of the block.
+	Class    RuneClass
+	Language Language // Index of the language (range table), UnknownLanguage for non-letters.
+}
+
+// SmartTokenSubtoken is a subtoken of a token with the reasoning behind its info.
+type SmartTokenSubtoken struct {
+	Subtoken string
+	Start    int    // Byte offset of the subtoken in the token.
+	End      int    // Exclusive end of the subtoken.
+	Blocks   [2]int // Indices of the first block and of the block following the last one.
+	Info     SmartTokenInfo
+	Decision LanguageDecision // Rule used to detect the language.
+	Skipped  bool             // Subtoken consists of stop words or exceeds the limit of the policy and is not emitted.
+}
+
+// SmartTokenExplanation describes how a token is tokenized. Offsets refer to
+// the normalized token. N-grams are not explained.
+type SmartTokenExplanation struct {
+	Token     string // Normalized token.
+	Blocks    []SmartTokenBlock
+	Depth     int // Maximal number of blocks in a subtoken chosen by the policy.
+	Subtokens []SmartTokenSubtoken
+}
+
+// Explain tokenizes a single token and reports the intermediate state: blocks,
+// the depth chosen by the policy and the language decision of every subtoken.
+// Subtokens are listed in the order they are emitted.
+func (st *SmartToken) Explain(token string) SmartTokenExplanation {
+	normalized, _ := st.normalize(token)
+	explanation := SmartTokenExplanation{
+		Token: normalized,
+		Depth: 1,
+	}
+	if len(normalized) == 0 {
+		return explanation
+	}
+
+	blocks := st.getBlocks(normalized)
+	explanation.Depth = st.getDepth(normalized, blocks)
+	bs, rc, rt := blocks.bs, blocks.rc, blocks.rt
+	for index := range rc {
+		explanation.Blocks = append(explanation.Blocks, SmartTokenBlock{
+			Start:    bs[index].(int),
+			End:      bs[index+1].(int),
+			Class:    rc[index].(RuneClass),
+			Language: rt[index].(Language),
+		})
+	}
+
+	stop := st.getStopBlocks(normalized, blocks)
+	limit := st.getLimit()
+	for first := range rc {
+		for last := first + 1; last <= len(rc) && last-first <= explanation.Depth; last++ {
+			left, right := bs[first].(int), bs[last].(int)
+			var info SmartTokenInfo
+			var decision LanguageDecision
+			info.DetectedLanguage, info.DetectedBase, decision = st.detectLanguage(normalized, bs[first:last+1], rc[first:last], rt[first:last])
+			info.Stem = st.stem(normalized[left:right], info)
+			skipped := limit == 0 || stop.skip(left, right)
+			if !skipped && limit > 0 {
+				limit--
+			}
+			explanation.Subtokens = append(explanation.Subtokens, SmartTokenSubtoken{
+				Subtoken: normalized[left:right],
+				Start:    left,
+				End:      right,
+				Blocks:   [2]int{first, last},
+				Info:     info,
+				Decision: decision,
+				Skipped:  skipped,
+			})
+		}
+	}
+	return explanation
+}
@@ -0,0 +1,51 @@
+package gotoken
+
+import (
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+
+	explanation := st.Explain("hello你好")
+	assert.Equal("hello你好", explanation.Token)
+	assert.Equal(10, explanation.Depth)
+	assert.Equal([]SmartTokenBlock{
+		SmartTokenBlock{Start: 0, End: 5, Class: Letter, Language: 0},
+		SmartTokenBlock{Start: 5, End: 11, Class: Letter, Language: UnknownLanguage},
+	}, explanation.Blocks)
+	assert.Equal([]SmartTokenSubtoken{
+		SmartTokenSubtoken{Subtoken: "hello", Start: 0, End: 5, Blocks: [2]int{0, 1},
+			Info: SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 5}}, Decision: DecisionSingle},
+		SmartTokenSubtoken{Subtoken: "hello你好", Start: 0, End: 11, Blocks: [2]int{0, 2},
+			Info: SmartTokenInfo{DetectedLanguage: UnknownLanguage, DetectedBase: [2]int{0, 11}}, Decision: DecisionSingle},
+		SmartTokenSubtoken{Subtoken: "你好", Start: 5, End: 11, Blocks: [2]int{1, 2},
+			Info: SmartTokenInfo{DetectedLanguage: UnknownLanguage, DetectedBase: [2]int{0, 6}}, Decision: DecisionSingle},
+	}, explanation.Subtokens)
+
+	assert.Empty(st.Explain("").Blocks)
+}
+
+func TestExplainMatchesTokenizer(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(2, 3, 4, 1)
+	st.AddLanguage("en", unicode.Latin)
+	st.AddLanguage("ru", unicode.Cyrillic)
+	st.SetStopWords(0, StopWords{"the": true})
+	st.SetNormalization(NormalizeCaseFold)
+
+	for _, token := range []string{"mail.ru-сервисы", "The-END", "123", "a.b.c.d.e.f"} {
+		explained := make(map[string]SmartTokenInfo)
+		for _, subtoken := range st.Explain(token).Subtokens {
+			if !subtoken.Skipped {
+				explained[subtoken.Subtoken] = subtoken.Info
+			}
+		}
+		assert.Equal(st.TokenizeString(token), explained, "explanation of '%v'", token)
+	}
+}
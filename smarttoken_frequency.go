@@ -0,0 +1,59 @@
+package gotoken
+
+// SmartTokenFrequency keeps occurrence statistics of a subtoken.
+type SmartTokenFrequency struct {
+	Count     int                    // Number of occurrences.
+	Documents int                    // Number of documents containing the subtoken.
+	Infos     map[SmartTokenInfo]int // SmartTokenInfo -> Count(Occurrence).
+}
+
+// SmartTokenFrequencies accumulates subtoken statistics over a collection of documents.
+type SmartTokenFrequencies struct {
+	Documents int
+	Tokens    map[string]*SmartTokenFrequency
+}
+
+// NewSmartTokenFrequencies creates an empty collection.
+func NewSmartTokenFrequencies() *SmartTokenFrequencies {
+	return &SmartTokenFrequencies{
+		Tokens: make(map[string]*SmartTokenFrequency),
+	}
+}
+
+// Add accumulates statistics of a single document returned by CountString.
+func (f *SmartTokenFrequencies) Add(document map[string]*SmartTokenFrequency) {
+	f.Documents++
+	for token, frequency := range document {
+		total, ok := f.Tokens[token]
+		if !ok {
+			total = &SmartTokenFrequency{Infos: make(map[SmartTokenInfo]int)}
+			f.Tokens[token] = total
+		}
+		total.Count += frequency.Count
+		total.Documents++
+		for info, count := range frequency.Infos {
+			total.Infos[info] += count
+		}
+	}
+}
+
+// CountString starts SmartToken tokenization process on a string treated as
+// a single document and counts occurrences of every subtoken.
+func (st *SmartToken) CountString(source string) map[string]*SmartTokenFrequency {
+	tokens := make(map[string]*SmartTokenFrequency)
+	st.splitTokens(source, func(offset int, token string) {
+		st.processToken(token, func(subtoken string, left int, right int, depth int, info SmartTokenInfo) {
+			if depth == 0 {
+				return
+			}
+			frequency, ok := tokens[subtoken]
+			if !ok {
+				frequency = &SmartTokenFrequency{Documents: 1, Infos: make(map[SmartTokenInfo]int)}
+				tokens[subtoken] = frequency
+			}
+			frequency.Count++
+			frequency.Infos[info]++
+		})
+	})
+	return tokens
+}
@@ -0,0 +1,34 @@
+package gotoken
+
+import (
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountString(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+	st.AddRangeTable(unicode.Cyrillic)
+
+	hello := SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 5}}
+	dot := SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}}
+
+	first := st.CountString("hello hello. hello")
+	assert.Equal(&SmartTokenFrequency{Count: 3, Documents: 1, Infos: map[SmartTokenInfo]int{hello: 3}}, first["hello"])
+	assert.Equal(&SmartTokenFrequency{Count: 1, Documents: 1, Infos: map[SmartTokenInfo]int{hello: 1}}, first["hello."])
+	assert.Equal(&SmartTokenFrequency{Count: 1, Documents: 1, Infos: map[SmartTokenInfo]int{dot: 1}}, first["."])
+
+	second := st.CountString("hello привет")
+	assert.Equal(&SmartTokenFrequency{Count: 1, Documents: 1, Infos: map[SmartTokenInfo]int{hello: 1}}, second["hello"])
+
+	frequencies := NewSmartTokenFrequencies()
+	frequencies.Add(first)
+	frequencies.Add(second)
+	assert.Equal(2, frequencies.Documents)
+	assert.Equal(&SmartTokenFrequency{Count: 4, Documents: 2, Infos: map[SmartTokenInfo]int{hello: 4}}, frequencies.Tokens["hello"])
+	assert.Equal(&SmartTokenFrequency{Count: 1, Documents: 1, Infos: map[SmartTokenInfo]int{dot: 1}}, frequencies.Tokens["."])
+	assert.Equal(1, frequencies.Tokens["привет"].Documents)
+}
@@ -0,0 +1,54 @@
+package gotoken
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/rivo/uniseg"
+)
+
+// SetGraphemeClusters tells tokenizer to split tokens into blocks by extended
+// grapheme clusters (UAX #29) instead of runes, so blocks and subtokens never
+// end inside a cluster. A cluster takes the class and the language of its
+// first rune, except emoji sequences (ZWJ sequences, flags, keycaps, skin
+// tones and presentation selectors) which are single Emoji units. Joiners
+// inside words (Indic conjuncts and chillus) keep the word a single block.
+func (st *SmartToken) SetGraphemeClusters(enabled bool) {
+	st.graphemes = enabled
+}
+
+// forEachGrapheme calls fn for every extended grapheme cluster of the token.
+func forEachGrapheme(token string, fn func(index int, cluster string)) {
+	state := -1
+	for index := 0; index < len(token); {
+		var cluster string
+		cluster, _, _, state = uniseg.FirstGraphemeClusterInString(token[index:], state)
+		fn(index, cluster)
+		index += len(cluster)
+	}
+}
+
+// graphemeClass returns the class and the language of the cluster. Clusters
+// starting with an emoji (flags and ZWJ sequences among them) and keycaps are
+// Emoji, other clusters take the class and the language of their first rune:
+// a zero width joiner inside a word does not make it an emoji.
+func graphemeClass(cluster string, table *runeTable) (RuneClass, Language) {
+	r, _ := utf8.DecodeRuneInString(cluster)
+	class, language := table.lookup(r)
+	if class == Emoji || unicode.Is(unicode.Regional_Indicator, r) || isKeycap(cluster) {
+		return Emoji, UnknownLanguage
+	}
+	return class, language
+}
+
+// isKeycap tells whether the cluster is a keycap sequence: a digit, "#" or "*"
+// optionally followed by the emoji presentation selector and followed by the
+// combining enclosing keycap.
+func isKeycap(cluster string) bool {
+	if cluster == "" || !strings.ContainsRune("0123456789#*", rune(cluster[0])) {
+		return false
+	}
+	rest := strings.TrimPrefix(cluster[1:], "\ufe0f")
+	return rest == "\u20e3"
+}
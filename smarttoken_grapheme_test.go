@@ -0,0 +1,87 @@
+package gotoken
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphemeClusters(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+	st.SetGraphemeClusters(true)
+
+	family := "\U0001f468\u200d\U0001f469\u200d\U0001f467"
+	testSet := []tokenizerTestSet{
+		tokenizerTestSet{
+			input: "hi" + family, // ZWJ sequence is a single unit.
+			output: map[string]SmartTokenInfo{
+				"hi":          SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 2}},
+				family:        SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"hi" + family: SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 2}},
+			},
+		},
+		tokenizerTestSet{
+			input: "go\U0001f1fa\U0001f1f8", // Flag.
+			output: map[string]SmartTokenInfo{
+				"go":                     SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 2}},
+				"\U0001f1fa\U0001f1f8":   SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"go\U0001f1fa\U0001f1f8": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 2}},
+			},
+		},
+		tokenizerTestSet{
+			input: "#\u20e3", // Keycap without the presentation selector.
+			output: map[string]SmartTokenInfo{
+				"#\u20e3": SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+			},
+		},
+		tokenizerTestSet{
+			input: "1\ufe0f\u20e3x", // Keycap is an emoji, not a digit.
+			output: map[string]SmartTokenInfo{
+				"1\ufe0f\u20e3":  SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"x":              SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 1}},
+				"1\ufe0f\u20e3x": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{7, 8}},
+			},
+		},
+	}
+	for _, test := range testSet {
+		result := st.TokenizeString(test.input)
+		assert.True(reflect.DeepEqual(result, test.output), fmt.Sprintf("wrong tokenization of '%v' -> %v", test.input, result))
+	}
+
+	// Zero width joiners inside words do not make emoji.
+	devanagari := st.AddLanguage("hi", unicode.Devanagari)
+	malayalam := st.AddLanguage("ml", unicode.Malayalam)
+	for word, language := range map[string]Language{
+		"\u0915\u094d\u200d\u0937":       devanagari, // Half form of "क" in "क्‍ष".
+		"\u0d05\u0d35\u0d28\u0d4d\u200d": malayalam,  // Chillu in "അവന്‍".
+	} {
+		result := st.TokenizeString(word)
+		assert.Equal(map[string]SmartTokenInfo{word: SmartTokenInfo{DetectedLanguage: language, DetectedBase: [2]int{0, len(word)}}}, result, "'%v' is a single word", word)
+		explanation := st.Explain(word)
+		assert.Len(explanation.Blocks, 1, "'%v' is a single block", word)
+	}
+
+	st.SetGraphemeClusters(false)
+	assert.Len(st.TokenizeString(family), 14, "runes of the ZWJ sequence are separate blocks")
+}
+
+func TestGraphemeNGrams(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+	st.SetGraphemeClusters(true)
+	st.SetNGrams(NGrams{Min: 2, Max: 2})
+
+	var ngrams []string
+	for _, o := range st.TokenizeStringOccurrences("cafe\u0301") {
+		if o.Depth == 0 {
+			ngrams = append(ngrams, o.Token)
+		}
+	}
+	assert.Equal([]string{"ca", "af", "fe\u0301"}, ngrams)
+}
@@ -0,0 +1,199 @@
+package gotoken
+
+import (
+	"strconv"
+	"unicode"
+)
+
+// Language identifies a language registered in SmartToken.
+type Language int
+
+// UnknownLanguage is detected for subtokens without letters of registered languages.
+const UnknownLanguage Language = -1
+
+type language struct {
+	name   string
+	tables []*unicode.RangeTable
+}
+
+func (l *language) contains(r rune) bool {
+	for _, table := range l.tables {
+		if unicode.Is(table, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddLanguage pushes new language into tokenizer. Tables of a language which
+// is already registered under the same name are added to it. Languages with
+// an empty name are anonymous and never merged.
+func (st *SmartToken) AddLanguage(name string, tables ...*unicode.RangeTable) Language {
+	if name != "" {
+		if l, ok := st.LookupLanguage(name); ok {
+			st.languages[l].tables = append(st.languages[l].tables, tables...)
+			st.resetRuneTable()
+			return l
+		}
+	}
+	st.languages = append(st.languages, language{name: name, tables: tables})
+	st.resetRuneTable()
+	return Language(len(st.languages) - 1)
+}
+
+// LookupLanguage finds registered language by name.
+func (st *SmartToken) LookupLanguage(name string) (Language, bool) {
+	for index, language := range st.languages {
+		if language.name == name {
+			return Language(index), true
+		}
+	}
+	return UnknownLanguage, false
+}
+
+// LanguageName returns the name the language was registered with.
+func (st *SmartToken) LanguageName(l Language) string {
+	if l < 0 || int(l) >= len(st.languages) {
+		return ""
+	}
+	return st.languages[l].name
+}
+
+// LanguageDecision tells which rule was used to detect the language of a subtoken.
+type LanguageDecision int
+
+const (
+	DecisionNoLetters LanguageDecision = iota // No letter blocks: "123---123".
+	DecisionAmbiguous                         // Every word switches script more than once: "mailприветhello".
+	DecisionSingle                            // All words share the same language: "карабас-барабас".
+	DecisionMajority                          // Language with the largest number of letters: "mail.ru-сервисы".
+	DecisionTieBreak                          // Languages tie, the language of the last word wins: "mail.ru-сервис".
+)
+
+func (d LanguageDecision) String() string {
+	switch d {
+	case DecisionNoLetters:
+		return "NoLetters"
+	case DecisionAmbiguous:
+		return "Ambiguous"
+	case DecisionSingle:
+		return "Single"
+	case DecisionMajority:
+		return "Majority"
+	case DecisionTieBreak:
+		return "TieBreak"
+	}
+	return "LanguageDecision(" + strconv.Itoa(int(d)) + ")"
+}
+
+// languageWord is a run of adjacent letter blocks.
+type languageWord struct {
+	first    int      // Index of the first block.
+	last     int      // Index of the last block.
+	language Language // Language of the last block.
+	weight   int      // Number of runes.
+	switches int      // Number of script switches.
+}
+
+// ambiguous tells whether the word switches script more than once. A single
+// switch is a foreign root with a native suffix ("mailка") and the word takes
+// the language of the suffix.
+func (w languageWord) ambiguous() bool {
+	return w.switches > 1
+}
+
+// DetectLanguage detects the language of the whole token and tells which rule was used.
+func (st *SmartToken) DetectLanguage(token string) (SmartTokenInfo, LanguageDecision) {
+	var info SmartTokenInfo
+	var decision LanguageDecision
+	if len(token) == 0 {
+		info.DetectedLanguage = UnknownLanguage
+		return info, DecisionNoLetters
+	}
+	blocks := st.getBlocks(token)
+	info.DetectedLanguage, info.DetectedBase, decision = st.detectLanguage(token, blocks.bs, blocks.rc, blocks.rt)
+	return info, decision
+}
+
+// detectLanguage detects the language of the subtoken token[bs[0]:bs[len(bs)-1]]
+// consisting of blocks with rune classes rc and languages rt.
+//
+// Adjacent letter blocks (split by a script switch or by a dictionary) form
+// words. Every word votes for its language with the number of its letters
+// (combining marks attached to them do not count), and the language with the
+// most votes wins. Ties are resolved in favour of the last word. The base spans
+// all words of the winner. Blocks of other classes never vote.
+func (st *SmartToken) detectLanguage(token string, bs []interface{}, rc []interface{}, rt []interface{}) (Language, [2]int, LanguageDecision) {
+	var words []languageWord
+	table := st.runeTable()
+	for index := range rc {
+		if rc[index] != Letter {
+			continue
+		}
+		weight := 0
+		for _, r := range token[bs[index].(int):bs[index+1].(int)] {
+			if class, _ := table.lookup(r); class != Mark {
+				weight++
+			}
+		}
+		if index > 0 && rc[index-1] == Letter {
+			word := &words[len(words)-1]
+			if word.language != rt[index].(Language) {
+				word.switches++
+			}
+			word.last = index
+			word.language = rt[index].(Language)
+			word.weight += weight
+		} else {
+			words = append(words, languageWord{first: index, last: index, language: rt[index].(Language), weight: weight})
+		}
+	}
+	if len(words) == 0 {
+		return UnknownLanguage, [2]int{0, 0}, DecisionNoLetters
+	}
+
+	weights := make(map[Language]int) // Language -> Count(Rune).
+	for _, word := range words {
+		if !word.ambiguous() {
+			weights[word.language] += word.weight
+		}
+	}
+	if len(weights) == 0 {
+		return UnknownLanguage, [2]int{0, 0}, DecisionAmbiguous
+	}
+
+	best := 0
+	for _, weight := range weights {
+		if weight > best {
+			best = weight
+		}
+	}
+	language := UnknownLanguage
+	for index := len(words) - 1; index >= 0; index-- {
+		if !words[index].ambiguous() && weights[words[index].language] == best {
+			language = words[index].language
+			break
+		}
+	}
+
+	decision := DecisionSingle
+	if len(weights) > 1 {
+		decision = DecisionMajority
+		for lang, weight := range weights {
+			if weight == best && lang != language {
+				decision = DecisionTieBreak
+			}
+		}
+	}
+
+	first, last := -1, -1
+	for _, word := range words {
+		if !word.ambiguous() && word.language == language {
+			if first == -1 {
+				first = word.first
+			}
+			last = word.last
+		}
+	}
+	return language, [2]int{bs[first].(int) - bs[0].(int), bs[last+1].(int) - bs[0].(int)}, decision
+}
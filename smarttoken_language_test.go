@@ -0,0 +1,68 @@
+package gotoken
+
+import (
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+	st.AddRangeTable(unicode.Cyrillic)
+
+	testSet := []struct {
+		input    string
+		info     SmartTokenInfo
+		decision LanguageDecision
+	}{
+		{"hello", SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 5}}, DecisionSingle},
+		{"привет", SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 12}}, DecisionSingle},
+		{"123", SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}}, DecisionNoLetters},
+		{"mailка", SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 8}}, DecisionSingle},
+		{"привет---", SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 12}}, DecisionSingle},
+		{"---привет", SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{3, 15}}, DecisionSingle},
+		{"---123", SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}}, DecisionNoLetters},
+		{"mailприветhello", SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}}, DecisionAmbiguous},
+		{"mailка---", SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 8}}, DecisionSingle},
+		{"карабас-барабас", SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 29}}, DecisionSingle},
+		{"css-стили", SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{4, 14}}, DecisionMajority},
+		{"---mailка", SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{3, 11}}, DecisionSingle},
+		{"привет---123", SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 12}}, DecisionSingle},
+		{"---привет---", SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{3, 15}}, DecisionSingle},
+		{"123---привет", SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{6, 18}}, DecisionSingle},
+		{"123---123", SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}}, DecisionNoLetters},
+		{"a-b-c", SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 5}}, DecisionSingle},
+		{"mail.ru-сервис", SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{8, 20}}, DecisionTieBreak},
+		{"mail.ru-сервисы", SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{8, 22}}, DecisionMajority},
+		{"стили-css", SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 10}}, DecisionMajority},
+		{"mailприветhello-мир", SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{22, 28}}, DecisionSingle},
+	}
+	for _, test := range testSet {
+		info, decision := st.DetectLanguage(test.input)
+		assert.Equal(test.info, info, "wrong language of '%v'", test.input)
+		assert.Equal(test.decision, decision, "wrong decision for '%v'", test.input)
+	}
+}
+
+func TestAddLanguage(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	en := st.AddLanguage("en", unicode.Latin)
+	ru := st.AddLanguage("ru", unicode.Cyrillic)
+	assert.Equal(en, st.AddLanguage("en", unicode.Greek), "tables of the same language")
+
+	l, ok := st.LookupLanguage("ru")
+	assert.True(ok)
+	assert.Equal(ru, l)
+	_, ok = st.LookupLanguage("zh")
+	assert.False(ok)
+	assert.Equal("en", st.LanguageName(en))
+	assert.Equal("", st.LanguageName(UnknownLanguage))
+
+	assert.Equal(map[string]SmartTokenInfo{
+		"helloαβγ": SmartTokenInfo{DetectedLanguage: en, DetectedBase: [2]int{0, 11}},
+	}, st.TokenizeString("helloαβγ"))
+}
@@ -0,0 +1,115 @@
+package gotoken
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// NGrams describes character n-grams emitted for every letter block in
+// addition to subtokens.
+type NGrams struct {
+	Min    int  // The shortest n-gram in runes (grapheme clusters if enabled).
+	Max    int  // The longest n-gram in runes, zero disables n-grams.
+	Marker rune // Boundary marker surrounding every block, zero for none.
+}
+
+// SetNGrams tells tokenizer to emit character n-grams. N-grams carry the
+// language of their block, DetectedBase covers their letters in the source the
+// same way it does for subtokens. Markers take no bytes of the source. An n-gram may
+// look exactly like a subtoken, so n-grams are kept apart from subtokens: they
+// are returned by TokenizeStringNGrams and by occurrence methods with zero
+// Depth, while TokenizeString, TokenizeReader and CountString skip them.
+func (st *SmartToken) SetNGrams(n NGrams) {
+	if n.Min < 1 {
+		n.Min = 1
+	}
+	st.ngrams = n
+}
+
+// TokenizeStringNGrams starts SmartToken tokenization process on a string and
+// returns character n-grams only.
+func (st *SmartToken) TokenizeStringNGrams(source string) map[string]SmartTokenInfo {
+	ngrams := make(map[string]SmartTokenInfo) // N-gram -> Info.
+	st.splitTokens(source, func(offset int, token string) {
+		st.processToken(token, func(subtoken string, left int, right int, depth int, info SmartTokenInfo) {
+			if depth == 0 {
+				ngrams[subtoken] = info
+			}
+		})
+	})
+	return ngrams
+}
+
+// ngramUnit is a rune (or a grapheme cluster) of a block or a boundary marker.
+type ngramUnit struct {
+	text   string
+	left   int // Byte offset inside the token.
+	right  int
+	marker bool
+}
+
+// getNGrams calls emit for every n-gram of every letter block of the token
+// except stop words. Offsets map byte offsets of the token to byte offsets of
+// the original token.
+func (st *SmartToken) getNGrams(token string, blocks *tokenBlocks, offsets *offsetMap, stop *stopBlocks, emit func(subtoken string, left int, right int, depth int, info SmartTokenInfo)) {
+	var marker string
+	if st.ngrams.Marker != 0 {
+		marker = string(st.ngrams.Marker)
+	}
+
+	bs, rc, rt := blocks.bs, blocks.rc, blocks.rt
+	for block := range rc {
+		if rc[block] != Letter {
+			continue
+		}
+		left, right := bs[block].(int), bs[block+1].(int)
+		if stop.skip(left, right) {
+			continue
+		}
+
+		var units []ngramUnit
+		if marker != "" {
+			units = append(units, ngramUnit{text: marker, left: left, right: left, marker: true})
+		}
+		if st.graphemes {
+			forEachGrapheme(token[left:right], func(index int, cluster string) {
+				units = append(units, ngramUnit{text: cluster, left: left + index, right: left + index + len(cluster)})
+			})
+		} else {
+			for index, r := range token[left:right] {
+				units = append(units, ngramUnit{text: token[left+index : left+index+utf8.RuneLen(r)], left: left + index, right: left + index + utf8.RuneLen(r)})
+			}
+		}
+		if marker != "" {
+			units = append(units, ngramUnit{text: marker, left: right, right: right, marker: true})
+		}
+
+		for n := st.ngrams.Min; n <= st.ngrams.Max; n++ {
+			for first := 0; first+n <= len(units); first++ {
+				gram := units[first : first+n]
+				letters := gram
+				if letters[0].marker {
+					letters = letters[1:]
+				}
+				if len(letters) > 0 && letters[len(letters)-1].marker {
+					letters = letters[:len(letters)-1]
+				}
+				if len(letters) == 0 {
+					continue
+				}
+
+				var text strings.Builder
+				for _, unit := range gram {
+					text.WriteString(unit.text)
+				}
+				sourceLeft, sourceRight := offsets.span(gram[0].left, gram[n-1].right)
+				baseLeft, baseRight := offsets.span(letters[0].left, letters[len(letters)-1].right)
+				info := SmartTokenInfo{
+					DetectedLanguage: rt[block].(Language),
+					DetectedBase:     [2]int{baseLeft - sourceLeft, baseRight - sourceLeft},
+				}
+				emit(text.String(), sourceLeft, sourceRight, 0, info)
+			}
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package gotoken
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNGrams(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+	st.AddRangeTable(unicode.Cyrillic)
+
+	st.SetNGrams(NGrams{Min: 2, Max: 3})
+	result := st.TokenizeString("abc.яю")
+	expected := map[string]SmartTokenInfo{
+		"abc":    SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 3}},
+		".":      SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+		"яю":     SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 4}},
+		"abc.":   SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 3}},
+		".яю":    SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{1, 5}},
+		"abc.яю": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 3}},
+	}
+	assert.True(reflect.DeepEqual(result, expected), fmt.Sprintf("n-grams mixed with subtokens -> %v", result))
+
+	// N-grams "abc" and "яю" look like subtokens but do not replace them.
+	result = st.TokenizeStringNGrams("abc.яю")
+	expected = map[string]SmartTokenInfo{
+		"ab":  SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 2}},
+		"bc":  SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 2}},
+		"abc": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 3}},
+		"яю":  SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 4}},
+	}
+	assert.True(reflect.DeepEqual(result, expected), fmt.Sprintf("wrong n-grams -> %v", result))
+
+	st.SetNGrams(NGrams{Min: 3, Max: 3, Marker: '$'})
+	result = st.TokenizeStringNGrams("яю")
+	expected = map[string]SmartTokenInfo{
+		"$яю": SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 4}},
+		"яю$": SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 4}},
+	}
+	assert.True(reflect.DeepEqual(result, expected), fmt.Sprintf("wrong n-grams with markers -> %v", result))
+
+	occurrences := st.TokenizeStringOccurrences("x яю")
+	assert.Equal(SmartTokenOccurrence{Token: "$яю", Info: SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 4}}, ByteStart: 2, ByteEnd: 6, RuneStart: 2, RuneEnd: 4, Index: 1, Depth: 0}, occurrences[len(occurrences)-2])
+
+	// The base is measured in the source, where markers take no bytes.
+	st.SetNGrams(NGrams{Min: 2, Max: 2, Marker: '^'})
+	for _, o := range st.TokenizeStringOccurrences("abc") {
+		if o.Depth != 0 {
+			continue
+		}
+		left, right := o.ByteStart+o.Info.DetectedBase[0], o.ByteStart+o.Info.DetectedBase[1]
+		assert.True(left >= o.ByteStart && right <= o.ByteEnd, "base of '%v' is outside of it", o.Token)
+		assert.Equal(strings.Trim(o.Token, "^"), "abc"[left:right], "base of '%v'", o.Token)
+	}
+}
@@ -0,0 +1,137 @@
+package gotoken
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Normalization is a set of transformations applied to every token before it
+// is split into subtokens.
+type Normalization int
+
+const (
+	NormalizeNFC             Normalization = 1 << iota // Canonical composition: NFD "é" becomes NFC "é".
+	NormalizeNFKC                                      // Compatibility composition: "ﬁ" becomes "fi".
+	NormalizeCaseFold                                  // Unicode case folding: "HELLO" becomes "hello".
+	NormalizeStripDiacritics                           // Removal of diacritics over Latin, Greek and Cyrillic letters: "é" becomes "e", implies NFC.
+)
+
+// diacriticLetters are letters with marks which are separate letters of their
+// alphabets and are never stripped.
+const diacriticLetters = "йЙўЎ"
+
+// SetNormalization tells tokenizer how to normalize tokens. Subtokens are
+// normalized, while DetectedBase and offsets still refer to the source.
+func (st *SmartToken) SetNormalization(n Normalization) {
+	st.normalization = n
+}
+
+// offsetMap maps byte offsets of a normalized token to byte offsets of the
+// token. Every byte of a normalization segment maps to the whole segment:
+// a span starting at it starts where the segment starts and a span ending
+// with it ends where the segment ends. A nil map is the identity.
+type offsetMap struct {
+	starts []int // Byte -> Start of its segment, the last entry is the length of the token.
+	ends   []int // Byte -> End of its segment.
+}
+
+// span maps the span [left, right) of the normalized token to the token.
+func (m *offsetMap) span(left int, right int) (int, int) {
+	if m == nil {
+		return left, right
+	}
+	if right <= left {
+		return m.starts[left], m.starts[left]
+	}
+	return m.starts[left], m.ends[right-1]
+}
+
+// normalize returns the normalized token and the map of its offsets to the
+// token. If the token is not normalized, the map is nil.
+func (st *SmartToken) normalize(token string) (string, *offsetMap) {
+	if st.normalization == 0 {
+		return token, nil
+	}
+
+	compose := norm.NFC
+	if st.normalization&NormalizeNFKC != 0 {
+		compose = norm.NFKC
+	}
+	iterate := compose
+	if st.normalization&NormalizeStripDiacritics != 0 {
+		iterate = norm.NFD
+		if compose == norm.NFKC {
+			iterate = norm.NFKD
+		}
+	}
+	var fold cases.Caser
+	if st.normalization&NormalizeCaseFold != 0 {
+		fold = cases.Fold()
+	}
+
+	var normalized strings.Builder
+	offsets := &offsetMap{
+		starts: make([]int, 0, len(token)+1),
+		ends:   make([]int, 0, len(token)),
+	}
+	push := func(start int, end int, segment string) {
+		if st.normalization&NormalizeStripDiacritics != 0 {
+			segment = stripDiacritics(segment, compose)
+		}
+		if st.normalization&NormalizeCaseFold != 0 {
+			segment = fold.String(segment)
+		}
+		normalized.WriteString(segment)
+		for i := 0; i < len(segment); i++ {
+			offsets.starts = append(offsets.starts, start)
+			offsets.ends = append(offsets.ends, end)
+		}
+	}
+
+	if st.normalization&(NormalizeNFC|NormalizeNFKC|NormalizeStripDiacritics) == 0 {
+		for index, r := range token {
+			push(index, index+utf8.RuneLen(r), string(r))
+		}
+	} else {
+		var iter norm.Iter
+		iter.InitString(iterate, token)
+		for !iter.Done() {
+			start := iter.Pos()
+			segment := string(iter.Next())
+			push(start, iter.Pos(), segment)
+		}
+	}
+	// An expansion may be returned in several segments consuming the source at
+	// the last one ("½" is "1", "⁄" and "2"): earlier ones end where it ends.
+	for i := len(offsets.ends) - 2; i >= 0; i-- {
+		if offsets.ends[i] == offsets.starts[i] {
+			offsets.ends[i] = offsets.ends[i+1]
+		}
+	}
+	offsets.starts = append(offsets.starts, len(token))
+	return normalized.String(), offsets
+}
+
+// stripDiacritics removes nonspacing marks from the decomposed segment and
+// composes it back. Only marks over Latin, Greek and Cyrillic letters are
+// diacritics: marks of other scripts (Devanagari vowel signs, Hebrew points,
+// Arabic harakat) are kept.
+func stripDiacritics(segment string, compose norm.Form) string {
+	composed := compose.String(segment)
+	if strings.ContainsAny(composed, diacriticLetters) {
+		return composed
+	}
+	if base, _ := utf8.DecodeRuneInString(segment); !unicode.In(base, unicode.Latin, unicode.Greek, unicode.Cyrillic) {
+		return composed
+	}
+	return compose.String(strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) {
+			return -1
+		}
+		return r
+	}, segment))
+}
@@ -0,0 +1,114 @@
+package gotoken
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalization(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+	st.AddRangeTable(unicode.Cyrillic)
+
+	testSet := []struct {
+		normalization Normalization
+		input         string
+		output        map[string]SmartTokenInfo
+	}{
+		{NormalizeCaseFold, "Hello HELLO ПРИВЕТ", map[string]SmartTokenInfo{
+			"hello":  SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 5}},
+			"привет": SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 12}},
+		}},
+		{NormalizeNFC, "cafe\u0301", map[string]SmartTokenInfo{
+			"caf\u00e9": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 6}},
+		}},
+		{NormalizeNFKC | NormalizeCaseFold, "ﬁLE", map[string]SmartTokenInfo{
+			"file": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 5}},
+		}},
+		{NormalizeStripDiacritics, "Cafe\u0301,\u0435\u0308лка", map[string]SmartTokenInfo{
+			"Cafe":      SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 6}},
+			"елка":      SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 10}},
+			",":         SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+			"Cafe,":     SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 6}},
+			",елка":     SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{1, 11}},
+			"Cafe,елка": SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{7, 17}},
+		}},
+		{NormalizeStripDiacritics | NormalizeCaseFold, "ЙОГУРТ", map[string]SmartTokenInfo{
+			"йогурт": SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 12}},
+		}},
+	}
+	for _, test := range testSet {
+		st.SetNormalization(test.normalization)
+		result := st.TokenizeString(test.input)
+		assert.True(reflect.DeepEqual(result, test.output), fmt.Sprintf("wrong tokenization of '%v' -> %v", test.input, result))
+	}
+}
+
+func TestNormalizationOccurrences(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+	st.SetNormalization(NormalizeNFKC | NormalizeCaseFold)
+
+	result := st.TokenizeStringOccurrences("x ﬁLE")
+	assert.Equal([]SmartTokenOccurrence{
+		SmartTokenOccurrence{Token: "x", Info: SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 1}}, ByteStart: 0, ByteEnd: 1, RuneStart: 0, RuneEnd: 1, Index: 0, Depth: 1},
+		SmartTokenOccurrence{Token: "file", Info: SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 5}}, ByteStart: 2, ByteEnd: 7, RuneStart: 2, RuneEnd: 5, Index: 1, Depth: 1},
+	}, result)
+}
+
+func TestNormalizationOffsets(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+	st.SetNormalization(NormalizeNFKC)
+
+	// Subtokens inside an expansion span the whole source segment.
+	type span struct {
+		Token     string
+		ByteStart int
+		ByteEnd   int
+		Base      [2]int
+	}
+	var result []span
+	for _, o := range st.TokenizeStringOccurrences("½a ﬁ1 ①b") {
+		result = append(result, span{o.Token, o.ByteStart, o.ByteEnd, o.Info.DetectedBase})
+	}
+	assert.Equal([]span{
+		{"1", 0, 2, [2]int{0, 0}},
+		{"1⁄", 0, 2, [2]int{0, 0}},
+		{"1⁄2", 0, 2, [2]int{0, 0}},
+		{"⁄", 0, 2, [2]int{0, 0}},
+		{"⁄2", 0, 2, [2]int{0, 0}},
+		{"2", 0, 2, [2]int{0, 0}},
+		{"1⁄2a", 0, 3, [2]int{2, 3}},
+		{"⁄2a", 0, 3, [2]int{2, 3}},
+		{"2a", 0, 3, [2]int{2, 3}},
+		{"a", 2, 3, [2]int{0, 1}},
+		{"fi", 4, 7, [2]int{0, 3}},
+		{"fi1", 4, 8, [2]int{0, 3}},
+		{"1", 7, 8, [2]int{0, 0}},
+		{"1", 9, 12, [2]int{0, 0}},
+		{"1b", 9, 13, [2]int{3, 4}},
+		{"b", 12, 13, [2]int{0, 1}},
+	}, result)
+}
+
+func TestStripDiacriticsScripts(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.SetNormalization(NormalizeStripDiacritics)
+
+	// Marks of other scripts are not diacritics.
+	for _, word := range []string{"नमस्ते", "สวัสดี", "שָׁלוֹם", "مَرْحَبًا"} {
+		_, ok := st.TokenizeString(word)[word]
+		assert.True(ok, "'%v' is kept", word)
+	}
+	_, ok := st.TokenizeString("café")["cafe"]
+	assert.True(ok, "Latin diacritics are stripped")
+}
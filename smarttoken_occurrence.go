@@ -0,0 +1,101 @@
+package gotoken
+
+import (
+	"io"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SmartTokenOccurrence describes a single occurrence of a subtoken in the source.
+// Token is normalized, while offsets are absolute positions in the source,
+// ends are exclusive. Parts of a token split by other separators, by code mode
+// or by word boundaries share its Index.
+type SmartTokenOccurrence struct {
+	Token     string
+	Info      SmartTokenInfo
+	ByteStart int
+	ByteEnd   int
+	RuneStart int
+	RuneEnd   int
+	Index     int // Index of the whitespace-delimited token of the source containing the subtoken.
+	Depth     int // Number of blocks the subtoken consists of, zero for n-grams.
+}
+
+// TokenizeStringOccurrences starts SmartToken tokenization process on a string
+// and returns every occurrence of every subtoken ordered by ByteStart and then
+// by ByteEnd. Occurrences with equal offsets (a subtoken and an n-gram) keep
+// the order they are produced in.
+func (st *SmartToken) TokenizeStringOccurrences(source string) []SmartTokenOccurrence {
+	var occurrences []SmartTokenOccurrence
+
+	index := -1
+	runeOffset := 0
+	byteOffset := 0
+	end := 0
+	st.splitTokens(source, func(offset int, token string) {
+		runeOffset += utf8.RuneCountInString(source[byteOffset:offset])
+		byteOffset = offset
+		if index < 0 || strings.IndexFunc(source[end:offset], unicode.IsSpace) >= 0 {
+			index++
+		}
+		end = offset + len(token)
+
+		occurrences = st.appendOccurrences(occurrences, token, index, offset, runeOffset)
+	})
+	return occurrences
+}
+
+// TokenizeReaderOccurrences is like TokenizeStringOccurrences but reads the
+// source from a stream. Occurrences are passed to emit token by token together
+// with the token itself and its byte offset in the stream.
+func (st *SmartToken) TokenizeReaderOccurrences(r io.Reader, emit func(offset int, token string, occurrences []SmartTokenOccurrence)) error {
+	index := -1
+	return st.readTokens(r, func(offset int, runeOffset int, token string, spaced bool) {
+		if spaced {
+			index++
+		}
+		emit(offset, token, st.appendOccurrences(nil, token, index, offset, runeOffset))
+	})
+}
+
+// appendOccurrences appends occurrences of subtokens of the token which starts
+// at the given byte and rune offsets ordered by position.
+func (st *SmartToken) appendOccurrences(occurrences []SmartTokenOccurrence, token string, index int, byteOffset int, runeOffset int) []SmartTokenOccurrence {
+	first := len(occurrences)
+	runes := runeOffsets(token)
+	st.processToken(token, func(subtoken string, left int, right int, depth int, info SmartTokenInfo) {
+		occurrences = append(occurrences, SmartTokenOccurrence{
+			Token:     subtoken,
+			Info:      info,
+			ByteStart: byteOffset + left,
+			ByteEnd:   byteOffset + right,
+			RuneStart: runeOffset + runes[left],
+			RuneEnd:   runeOffset + runes[right],
+			Index:     index,
+			Depth:     depth,
+		})
+	})
+	appended := occurrences[first:]
+	sort.SliceStable(appended, func(i, j int) bool {
+		if appended[i].ByteStart != appended[j].ByteStart {
+			return appended[i].ByteStart < appended[j].ByteStart
+		}
+		return appended[i].ByteEnd < appended[j].ByteEnd
+	})
+	return occurrences
+}
+
+// runeOffsets maps every byte offset at a rune boundary of s (including len(s))
+// to the number of runes before it.
+func runeOffsets(s string) []int {
+	offsets := make([]int, len(s)+1)
+	count := 0
+	for index := range s {
+		offsets[index] = count
+		count++
+	}
+	offsets[len(s)] = count
+	return offsets
+}
@@ -0,0 +1,99 @@
+package gotoken
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenizeStringOccurrences(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+	st.AddRangeTable(unicode.Cyrillic)
+
+	result := st.TokenizeStringOccurrences(" hello привет.ru hello")
+	expected := []SmartTokenOccurrence{
+		SmartTokenOccurrence{Token: "hello", Info: SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 5}}, ByteStart: 1, ByteEnd: 6, RuneStart: 1, RuneEnd: 6, Index: 0, Depth: 1},
+		SmartTokenOccurrence{Token: "привет", Info: SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 12}}, ByteStart: 7, ByteEnd: 19, RuneStart: 7, RuneEnd: 13, Index: 1, Depth: 1},
+		SmartTokenOccurrence{Token: "привет.", Info: SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 12}}, ByteStart: 7, ByteEnd: 20, RuneStart: 7, RuneEnd: 14, Index: 1, Depth: 2},
+		SmartTokenOccurrence{Token: "привет.ru", Info: SmartTokenInfo{DetectedLanguage: 1, DetectedBase: [2]int{0, 12}}, ByteStart: 7, ByteEnd: 22, RuneStart: 7, RuneEnd: 16, Index: 1, Depth: 3},
+		SmartTokenOccurrence{Token: ".", Info: SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}}, ByteStart: 19, ByteEnd: 20, RuneStart: 13, RuneEnd: 14, Index: 1, Depth: 1},
+		SmartTokenOccurrence{Token: ".ru", Info: SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{1, 3}}, ByteStart: 19, ByteEnd: 22, RuneStart: 13, RuneEnd: 16, Index: 1, Depth: 2},
+		SmartTokenOccurrence{Token: "ru", Info: SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 2}}, ByteStart: 20, ByteEnd: 22, RuneStart: 14, RuneEnd: 16, Index: 1, Depth: 1},
+		SmartTokenOccurrence{Token: "hello", Info: SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 5}}, ByteStart: 23, ByteEnd: 28, RuneStart: 17, RuneEnd: 22, Index: 2, Depth: 1},
+	}
+	assert.Equal(expected, result)
+}
+
+func TestTokenizeReaderOccurrences(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+	st.AddRangeTable(unicode.Cyrillic)
+	st.SetWordBoundaries(true)
+
+	source := " hello, \xffпривет.ru (don't) hello"
+	var streamed []SmartTokenOccurrence
+	var tokens []string
+	assert.NoError(st.TokenizeReaderOccurrences(strings.NewReader(source), func(offset int, token string, occurrences []SmartTokenOccurrence) {
+		assert.Equal(source[offset:offset+len(token)], token)
+		tokens = append(tokens, token)
+		streamed = append(streamed, occurrences...)
+	}))
+	assert.Equal([]string{"hello", "привет.ru", "don't", "hello"}, tokens)
+	assert.Equal(st.TokenizeStringOccurrences(source), streamed)
+}
+
+func TestTokenizeStringOccurrencesOrder(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	latin := st.AddLanguage("en", unicode.Latin)
+	dictionary := NewDictionary()
+	dictionary.Add("foot", 1)
+	dictionary.Add("ball", 1)
+	st.SetDictionary(latin, dictionary)
+	st.SetNGrams(NGrams{Min: 2, Max: 3})
+
+	result := st.TokenizeStringOccurrences("football x-ray")
+	assert.NotEmpty(result)
+	for index := 1; index < len(result); index++ {
+		previous, current := result[index-1], result[index]
+		assert.True(previous.ByteStart < current.ByteStart || previous.ByteStart == current.ByteStart && previous.ByteEnd <= current.ByteEnd,
+			"%v goes after %v", current, previous)
+	}
+}
+
+func TestTokenizeStringOccurrencesIndex(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+	st.SetCodeMode(true)
+
+	indexes := func(source string) map[string]int {
+		result := map[string]int{}
+		for _, occurrence := range st.TokenizeStringOccurrences(source) {
+			result[occurrence.Token] = occurrence.Index
+		}
+		var streamed []SmartTokenOccurrence
+		assert.NoError(st.TokenizeReaderOccurrences(strings.NewReader(source), func(offset int, token string, occurrences []SmartTokenOccurrence) {
+			streamed = append(streamed, occurrences...)
+		}))
+		assert.Equal(st.TokenizeStringOccurrences(source), streamed)
+		return result
+	}
+
+	result := indexes("x:=f(a,b) y")
+	assert.Equal(0, result["x"])
+	assert.Equal(0, result[":="])
+	assert.Equal(0, result["f"])
+	assert.Equal(0, result["b"])
+	assert.Equal(1, result["y"])
+
+	st.SetCodeMode(false)
+	st.SetSeparator(SeparatorAny(SeparatorSpace, SeparatorRunes(",")))
+	result = indexes("foo,bar\tbaz ,qux")
+	assert.Equal(map[string]int{"foo": 0, "bar": 0, "baz": 1, "qux": 2}, result)
+}
@@ -0,0 +1,37 @@
+package gotoken
+
+import (
+	"runtime"
+	"sync"
+)
+
+// TokenizeStrings starts SmartToken tokenization process on every source
+// using the given number of workers (GOMAXPROCS if it is not positive).
+// Results go in the order of sources.
+func (st *SmartToken) TokenizeStrings(sources []string, workers int) []map[string]SmartTokenInfo {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(sources) {
+		workers = len(sources)
+	}
+
+	results := make([]map[string]SmartTokenInfo, len(sources))
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for worker := 0; worker < workers; worker++ {
+		go func() {
+			defer wg.Done()
+			for index := range indices {
+				results[index] = st.TokenizeString(sources[index])
+			}
+		}()
+	}
+	for index := range sources {
+		indices <- index
+	}
+	close(indices)
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,63 @@
+package gotoken
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var parallelSources = []string{
+	"hello world",
+	"helloпривет hello你好 你好привет",
+	"aaa.bbb.ccc.ddd a.b.c.d",
+	"карабас-барабас css-стили mail.ru-сервис",
+	"123hello ...123 ☭...",
+	"",
+}
+
+func newParallelTokenizer() *SmartToken {
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+	st.AddRangeTable(unicode.Cyrillic)
+	return st
+}
+
+func TestTokenizeStringConcurrent(t *testing.T) {
+	assert := assert.New(t)
+	expected := make([]map[string]SmartTokenInfo, len(parallelSources))
+	for index, source := range parallelSources {
+		expected[index] = newParallelTokenizer().TokenizeString(source)
+	}
+
+	st := newParallelTokenizer()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		for index, source := range parallelSources {
+			wg.Add(1)
+			go func(index int, source string) {
+				defer wg.Done()
+				result := st.TokenizeString(source)
+				assert.True(reflect.DeepEqual(result, expected[index]), fmt.Sprintf("wrong tokenization of '%v' -> %v", source, result))
+			}(index, source)
+		}
+	}
+	wg.Wait()
+}
+
+func TestTokenizeStrings(t *testing.T) {
+	assert := assert.New(t)
+	st := newParallelTokenizer()
+
+	for _, workers := range []int{0, 1, 3, 100} {
+		results := st.TokenizeStrings(parallelSources, workers)
+		assert.Equal(len(parallelSources), len(results))
+		for index, source := range parallelSources {
+			assert.True(reflect.DeepEqual(results[index], st.TokenizeString(source)), fmt.Sprintf("wrong tokenization of '%v' with %v workers", source, workers))
+		}
+	}
+	assert.Empty(st.TokenizeStrings(nil, 0))
+}
@@ -1,5 +1,42 @@
 package gotoken
 
+import (
+	"fmt"
+)
+
 type SmartTokenPolicy interface {
 	GetDepth(length int) int
 }
+
+// SmartTokenShape describes a token for policies which need more than its length.
+type SmartTokenShape struct {
+	Length int // Number of runes.
+	Blocks int // Number of blocks.
+}
+
+// SmartTokenShapePolicy is implemented by policies which depend on the shape
+// of a token. The tokenizer prefers GetShapeDepth to GetDepth when a policy
+// implements it.
+type SmartTokenShapePolicy interface {
+	SmartTokenPolicy
+	GetShapeDepth(shape SmartTokenShape) int
+}
+
+// SmartTokenLimitPolicy is implemented by policies which also limit the number
+// of subtokens emitted from a single token. Subtokens beyond the limit are
+// dropped in the order they are emitted; skipped stop words do not count.
+type SmartTokenLimitPolicy interface {
+	SmartTokenPolicy
+	GetLimit() int
+}
+
+// PolicyError reports an invalid parameter of a policy constructor.
+type PolicyError struct {
+	Policy  string // Type of the policy: "depth", "count", "step", "log" or "blocks".
+	Param   string // Name of the parameter: "maxLength", "points[1].depth".
+	Message string
+}
+
+func (e *PolicyError) Error() string {
+	return fmt.Sprintf("gotoken: %s policy: %s %s", e.Policy, e.Param, e.Message)
+}
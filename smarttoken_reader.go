@@ -0,0 +1,82 @@
+package gotoken
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TokenizeReader starts SmartToken tokenization process on a stream.
+// Subtokens are passed to emit as soon as their token is read, so the same
+// subtoken is emitted once per token containing it. Collecting the emitted
+// subtokens into a map gives exactly the result of TokenizeString. Tokens are
+// not limited in length, but every token is kept in memory until it ends.
+func (st *SmartToken) TokenizeReader(r io.Reader, emit func(subtoken string, info SmartTokenInfo)) error {
+	return st.readTokens(r, func(offset int, runeOffset int, token string, spaced bool) {
+		st.processToken(token, func(subtoken string, left int, right int, depth int, info SmartTokenInfo) {
+			if depth > 0 {
+				emit(subtoken, info)
+			}
+		})
+	})
+}
+
+// readTokens calls fn for every token of the stream the same way splitTokens
+// does for a string and also passes the rune offset of the token and whether
+// white space precedes it (the first token counts as preceded). Invalid UTF-8
+// bytes are kept as is and count as single runes.
+func (st *SmartToken) readTokens(r io.Reader, fn func(offset int, runeOffset int, token string, spaced bool)) error {
+	reader := bufio.NewReader(r)
+	var token strings.Builder
+	start, runeStart := 0, 0
+	offset, runeOffset := 0, 0
+	spaced := true
+	flush := func() {
+		if token.Len() > 0 {
+			source := token.String()
+			previous, end := 0, 0
+			st.splitParts(source, func(part int, text string) {
+				runeStart += utf8.RuneCountInString(source[previous:part])
+				previous = part
+				if strings.IndexFunc(source[end:part], unicode.IsSpace) >= 0 {
+					spaced = true
+				}
+				end = part + len(text)
+				fn(start+part, runeStart, text, spaced)
+				spaced = false
+			})
+			token.Reset()
+		}
+	}
+	for {
+		r, size, err := reader.ReadRune()
+		if err == io.EOF {
+			flush()
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if st.isSeparator(r) {
+			flush()
+			if unicode.IsSpace(r) {
+				spaced = true
+			}
+		} else {
+			if token.Len() == 0 {
+				start, runeStart = offset, runeOffset
+			}
+			if r == utf8.RuneError && size == 1 {
+				reader.UnreadRune()
+				b, _ := reader.ReadByte()
+				token.WriteByte(b)
+			} else {
+				token.WriteRune(r)
+			}
+		}
+		offset += size
+		runeOffset++
+	}
+}
@@ -0,0 +1,54 @@
+package gotoken
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenizeReader(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+	st.AddRangeTable(unicode.Cyrillic)
+
+	inputs := []string{
+		"",
+		"   ",
+		"hello",
+		"hello world",
+		"  helloпривет\thello你好\n\n你好。再见。 ",
+		"aaa.bbb.ccc.ddd a.b.c.d карабас-барабас",
+	}
+	for _, input := range inputs {
+		expected := st.TokenizeString(input)
+		result := make(map[string]SmartTokenInfo)
+		err := st.TokenizeReader(iotest.OneByteReader(strings.NewReader(input)), func(subtoken string, info SmartTokenInfo) {
+			result[subtoken] = info
+		})
+		assert.NoError(err)
+		assert.True(reflect.DeepEqual(result, expected), fmt.Sprintf("wrong tokenization of '%v' -> %v", input, result))
+	}
+}
+
+func TestTokenizeReaderLongToken(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+
+	// Tokens longer than the buffer of bufio.Scanner are read as a whole.
+	input := "a " + strings.Repeat("bc", 1<<20) + "= \xff\xfeb"
+	expected := st.TokenizeString(input)
+	result := make(map[string]SmartTokenInfo)
+	err := st.TokenizeReader(strings.NewReader(input), func(subtoken string, info SmartTokenInfo) {
+		result[subtoken] = info
+	})
+	assert.NoError(err)
+	assert.Equal(len(expected), len(result))
+	assert.True(reflect.DeepEqual(result, expected), "wrong tokenization of a long token")
+}
@@ -0,0 +1,94 @@
+package gotoken
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/rivo/uniseg"
+)
+
+// SeparatorFunc tells whether the rune separates tokens.
+type SeparatorFunc func(r rune) bool
+
+// SeparatorSpace splits tokens on white space. It is the default separator.
+func SeparatorSpace(r rune) bool {
+	return unicode.IsSpace(r)
+}
+
+// SeparatorNonWordRune splits tokens on every rune which is not a letter,
+// a mark, a digit or connector punctuation ("_"). It looks at general
+// categories of single runes, so "3.14" and "don't" are split too: use
+// SetWordBoundaries for Unicode word boundaries.
+func SeparatorNonWordRune(r rune) bool {
+	return !unicode.In(r, unicode.L, unicode.M, unicode.Nd, unicode.Pc)
+}
+
+// SeparatorRunes splits tokens on white space and on the given runes.
+func SeparatorRunes(runes string) SeparatorFunc {
+	return func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(runes, r)
+	}
+}
+
+// SeparatorAny splits tokens on runes which any of separators splits on.
+func SeparatorAny(separators ...SeparatorFunc) SeparatorFunc {
+	return func(r rune) bool {
+		for _, separator := range separators {
+			if separator(r) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// SetSeparator tells tokenizer how to split the source into tokens. Nil
+// restores SeparatorSpace.
+func (st *SmartToken) SetSeparator(separator SeparatorFunc) {
+	st.separator = separator
+}
+
+func (st *SmartToken) isSeparator(r rune) bool {
+	if st.code && codeSeparator(r) {
+		return true
+	}
+	if st.separator == nil {
+		return unicode.IsSpace(r)
+	}
+	return st.separator(r)
+}
+
+// SetWordBoundaries tells tokenizer to split tokens at Unicode word boundaries
+// (UAX #29) after splitting the source by the separator. Segments without
+// letters and digits (punctuation between words) are dropped, so "3.14",
+// "don't" and "e.g" stay single tokens while "foo,bar" does not. It is
+// ignored in code mode.
+func (st *SmartToken) SetWordBoundaries(enabled bool) {
+	st.wordBoundaries = enabled
+}
+
+// splitParts calls fn for every part of the token produced by code mode or
+// word boundaries. Otherwise the token is passed as is.
+func (st *SmartToken) splitParts(token string, fn func(offset int, part string)) {
+	switch {
+	case st.code:
+		splitCode(token, fn)
+	case st.wordBoundaries:
+		splitWords(token, fn)
+	default:
+		fn(0, token)
+	}
+}
+
+// splitWords calls fn for every word of the token containing letters or digits.
+func splitWords(token string, fn func(offset int, word string)) {
+	state := -1
+	for offset := 0; offset < len(token); {
+		var word string
+		word, _, state = uniseg.FirstWordInString(token[offset:], state)
+		if strings.IndexFunc(word, func(r rune) bool { return unicode.IsLetter(r) || unicode.IsNumber(r) }) >= 0 {
+			fn(offset, word)
+		}
+		offset += len(word)
+	}
+}
@@ -0,0 +1,73 @@
+package gotoken
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSeparator(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+
+	word := SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 3}}
+	testSet := []struct {
+		separator SeparatorFunc
+		input     string
+		output    map[string]SmartTokenInfo
+	}{
+		{SeparatorRunes(","), "foo,bar baz", map[string]SmartTokenInfo{"foo": word, "bar": word, "baz": word}},
+		{SeparatorNonWordRune, "path/to/foo.bar", map[string]SmartTokenInfo{
+			"path": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 4}},
+			"to":   SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 2}},
+			"foo":  word,
+			"bar":  word,
+		}},
+		{SeparatorAny(SeparatorSpace, SeparatorRunes("/")), "foo/bar.baz", map[string]SmartTokenInfo{
+			"foo":     word,
+			"bar":     word,
+			"baz":     word,
+			".":       SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+			"bar.":    word,
+			".baz":    SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{1, 4}},
+			"bar.baz": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 7}},
+		}},
+	}
+	for _, test := range testSet {
+		st.SetSeparator(test.separator)
+		result := st.TokenizeString(test.input)
+		assert.True(reflect.DeepEqual(result, test.output), fmt.Sprintf("wrong tokenization of '%v' -> %v", test.input, result))
+
+		streamed := make(map[string]SmartTokenInfo)
+		assert.NoError(st.TokenizeReader(strings.NewReader(test.input), func(subtoken string, info SmartTokenInfo) {
+			streamed[subtoken] = info
+		}))
+		assert.True(reflect.DeepEqual(streamed, test.output), fmt.Sprintf("wrong streamed tokenization of '%v' -> %v", test.input, streamed))
+	}
+}
+
+func TestWordBoundaries(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	st.AddRangeTable(unicode.Latin)
+	st.SetWordBoundaries(true)
+
+	input := "3.14 don't, e.g. foo,bar (привет)"
+	var tokens []string
+	st.splitTokens(input, func(offset int, token string) {
+		assert.Equal(token, input[offset:offset+len(token)])
+		tokens = append(tokens, token)
+	})
+	assert.Equal([]string{"3.14", "don't", "e.g", "foo", "bar", "привет"}, tokens)
+
+	streamed := make(map[string]SmartTokenInfo)
+	assert.NoError(st.TokenizeReader(strings.NewReader(input), func(subtoken string, info SmartTokenInfo) {
+		streamed[subtoken] = info
+	}))
+	assert.Equal(st.TokenizeString(input), streamed)
+}
@@ -0,0 +1,39 @@
+package gotoken
+
+// Stemmer reduces a word to its stem.
+type Stemmer interface {
+	Stem(word string) string
+}
+
+// SetStemmer tells tokenizer to stem words of the language. Subtokens whose
+// base is a single word of the language get its stem in SmartTokenInfo.Stem.
+// Nil stemmer disables stemming.
+func (st *SmartToken) SetStemmer(l Language, s Stemmer) {
+	if s == nil {
+		delete(st.stemmers, l)
+		return
+	}
+	if st.stemmers == nil {
+		st.stemmers = make(map[Language]Stemmer)
+	}
+	st.stemmers[l] = s
+}
+
+// stem returns the stem of the base of the subtoken or an empty string.
+func (st *SmartToken) stem(subtoken string, info SmartTokenInfo) string {
+	s, ok := st.stemmers[info.DetectedLanguage]
+	if !ok || info.DetectedLanguage == UnknownLanguage {
+		return ""
+	}
+	base := subtoken[info.DetectedBase[0]:info.DetectedBase[1]]
+	if base == "" {
+		return ""
+	}
+	table := st.runeTable()
+	for _, r := range base {
+		if class, _ := table.lookup(r); class != Letter && class != Mark {
+			return ""
+		}
+	}
+	return s.Stem(base)
+}
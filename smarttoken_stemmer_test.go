@@ -0,0 +1,36 @@
+package gotoken
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenizerStemmer(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	en := st.AddLanguage("en", unicode.Latin)
+	ru := st.AddLanguage("ru", unicode.Cyrillic)
+	st.SetStemmer(en, EnglishStemmer)
+	st.SetStemmer(ru, RussianStemmer)
+
+	result := st.TokenizeString("Running... вагонов-вагоны")
+	expected := map[string]SmartTokenInfo{
+		"Running":        SmartTokenInfo{DetectedLanguage: en, DetectedBase: [2]int{0, 7}, Stem: "run"},
+		"...":            SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+		"Running...":     SmartTokenInfo{DetectedLanguage: en, DetectedBase: [2]int{0, 7}, Stem: "run"},
+		"вагонов":        SmartTokenInfo{DetectedLanguage: ru, DetectedBase: [2]int{0, 14}, Stem: "вагон"},
+		"-":              SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+		"вагоны":         SmartTokenInfo{DetectedLanguage: ru, DetectedBase: [2]int{0, 12}, Stem: "вагон"},
+		"вагонов-":       SmartTokenInfo{DetectedLanguage: ru, DetectedBase: [2]int{0, 14}, Stem: "вагон"},
+		"-вагоны":        SmartTokenInfo{DetectedLanguage: ru, DetectedBase: [2]int{1, 13}, Stem: "вагон"},
+		"вагонов-вагоны": SmartTokenInfo{DetectedLanguage: ru, DetectedBase: [2]int{0, 27}},
+	}
+	assert.True(reflect.DeepEqual(result, expected), fmt.Sprintf("wrong stemming -> %v", result))
+
+	st.SetStemmer(en, nil)
+	assert.Equal("", st.TokenizeString("running")["running"].Stem)
+}
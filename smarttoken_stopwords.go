@@ -0,0 +1,133 @@
+package gotoken
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// StopWords is a set of lowercased words which are not emitted as subtokens.
+type StopWords map[string]bool
+
+// LoadStopWords reads a stop word list from a text file. Words are separated
+// by white space, everything after '#' or '|' up to the end of line is a comment.
+func LoadStopWords(r io.Reader) (StopWords, error) {
+	words := make(StopWords)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if index := strings.IndexAny(line, "#|"); index >= 0 {
+			line = line[:index]
+		}
+		for _, word := range strings.Fields(line) {
+			words[strings.ToLower(word)] = true
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+// EnglishStopWords returns the Snowball stop word list for English without
+// contractions: the apostrophe splits them into separate blocks, which never
+// match the list as a whole.
+func EnglishStopWords() StopWords {
+	words, _ := LoadStopWords(strings.NewReader(englishStopWords))
+	return words
+}
+
+// RussianStopWords returns the Snowball stop word list for Russian.
+func RussianStopWords() StopWords {
+	words, _ := LoadStopWords(strings.NewReader(russianStopWords))
+	return words
+}
+
+// SetStopWords tells tokenizer not to emit subtokens whose letter blocks are
+// all stop words of their languages. Stop words are still emitted as parts of
+// longer subtokens: "и" is dropped, while "карабас-и-барабас" is kept. Nil
+// disables filtering.
+func (st *SmartToken) SetStopWords(l Language, words StopWords) {
+	if words == nil {
+		delete(st.stopWords, l)
+		return
+	}
+	if st.stopWords == nil {
+		st.stopWords = make(map[Language]StopWords)
+	}
+	st.stopWords[l] = words
+}
+
+// getStopBlocks marks blocks of the token which are stop words. It returns
+// nil if there are no stop words.
+func (st *SmartToken) getStopBlocks(token string, blocks *tokenBlocks) *stopBlocks {
+	if len(st.stopWords) == 0 {
+		return nil
+	}
+	bs, rc, rt := blocks.bs, blocks.rc, blocks.rt
+	sb := &stopBlocks{
+		bs:    bs,
+		rc:    rc,
+		stop:  make([]bool, len(rc)),
+		index: make(map[int]int),
+	}
+	for index := range rc {
+		sb.index[bs[index].(int)] = index
+		if rc[index] == Letter {
+			words := st.stopWords[rt[index].(Language)]
+			sb.stop[index] = words[strings.ToLower(token[bs[index].(int):bs[index+1].(int)])]
+		}
+	}
+	return sb
+}
+
+// stopBlocks keeps blocks of a token and tells which of them are stop words.
+type stopBlocks struct {
+	bs    []interface{}
+	rc    []interface{}
+	stop  []bool
+	index map[int]int // Byte offset -> Block.
+}
+
+// skip tells whether all letter blocks of the subtoken are stop words.
+func (sb *stopBlocks) skip(left int, right int) bool {
+	if sb == nil {
+		return false
+	}
+	letters := 0
+	for index := sb.index[left]; index < len(sb.rc) && sb.bs[index].(int) < right; index++ {
+		if sb.rc[index] == Letter {
+			if !sb.stop[index] {
+				return false
+			}
+			letters++
+		}
+	}
+	return letters > 0
+}
+
+const englishStopWords = `
+i me my myself we our ours ourselves you your yours yourself yourselves
+he him his himself she her hers herself it its itself they them their
+theirs themselves what which who whom this that these those am is are
+was were be been being have has had having do does did doing would
+should could ought cannot a an the and but if or because as until while
+of at by for with about against between into through during before after
+above below to from up down in out on off over under again further then
+once here there when where why how all any both each few more most other
+some such no nor not only own same so than too very
+`
+
+const russianStopWords = `
+и в во не что он на я с со как а то все она так его но да ты к у же
+вы за бы по только ее мне было вот от меня еще нет о из ему теперь
+когда даже ну вдруг ли если уже или ни быть был него до вас нибудь
+опять уж вам ведь там потом себя ничего ей может они тут где есть
+надо ней для мы тебя их чем была сам чтоб без будто чего раз тоже
+себе под будет ж тогда кто этот того потому этого какой совсем ним
+здесь этом один почти мой тем чтобы нее сейчас были куда зачем всех
+никогда можно при наконец два об другой хоть после над больше тот
+через эти нас про всего них какая много разве три эту моя впрочем
+хорошо свою этой перед иногда лучше чуть том нельзя такой им более
+всегда конечно всю между
+`
@@ -0,0 +1,57 @@
+package gotoken
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadStopWords(t *testing.T) {
+	assert := assert.New(t)
+	words, err := LoadStopWords(strings.NewReader("| comment\nThe and # comment\n\nof\n"))
+	assert.NoError(err)
+	assert.Equal(StopWords{"the": true, "and": true, "of": true}, words)
+	assert.True(EnglishStopWords()["the"])
+	assert.True(RussianStopWords()["и"])
+	assert.False(RussianStopWords()["карабас"])
+
+	// Stop words are matched against single letter blocks.
+	for _, words := range []StopWords{EnglishStopWords(), RussianStopWords()} {
+		for word := range words {
+			assert.Equal(-1, strings.IndexFunc(word, func(r rune) bool { return !unicode.IsLetter(r) }), "'%v' is not a single block", word)
+		}
+	}
+}
+
+func TestTokenizerStopWords(t *testing.T) {
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	en := st.AddLanguage("en", unicode.Latin)
+	ru := st.AddLanguage("ru", unicode.Cyrillic)
+	st.SetStopWords(en, EnglishStopWords())
+	st.SetStopWords(ru, RussianStopWords())
+	st.SetNormalization(NormalizeCaseFold)
+
+	result := st.TokenizeString("The кот-и-пёс")
+	expected := map[string]SmartTokenInfo{
+		"кот":       SmartTokenInfo{DetectedLanguage: ru, DetectedBase: [2]int{0, 6}},
+		"пёс":       SmartTokenInfo{DetectedLanguage: ru, DetectedBase: [2]int{0, 6}},
+		"-":         SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+		"кот-":      SmartTokenInfo{DetectedLanguage: ru, DetectedBase: [2]int{0, 6}},
+		"-пёс":      SmartTokenInfo{DetectedLanguage: ru, DetectedBase: [2]int{1, 7}},
+		"кот-и":     SmartTokenInfo{DetectedLanguage: ru, DetectedBase: [2]int{0, 9}},
+		"и-пёс":     SmartTokenInfo{DetectedLanguage: ru, DetectedBase: [2]int{0, 9}},
+		"кот-и-":    SmartTokenInfo{DetectedLanguage: ru, DetectedBase: [2]int{0, 9}},
+		"-и-пёс":    SmartTokenInfo{DetectedLanguage: ru, DetectedBase: [2]int{1, 10}},
+		"кот-и-пёс": SmartTokenInfo{DetectedLanguage: ru, DetectedBase: [2]int{0, 16}},
+	}
+	assert.True(reflect.DeepEqual(result, expected), fmt.Sprintf("wrong stop word filtering -> %v", result))
+
+	st.SetNGrams(NGrams{Min: 1, Max: 1})
+	result = st.TokenizeStringNGrams("The")
+	assert.Empty(result)
+}
@@ -0,0 +1,89 @@
+package gotoken
+
+// runeTablePage is a page of the rune table. Every entry keeps the rune class
+// in the lower 4 bits and the language (shifted by one) in the upper 12 bits.
+type runeTablePage [256]uint16
+
+const (
+	runeTableClassBits    = 4
+	runeTableClassMask    = 1<<runeTableClassBits - 1
+	runeTableMaxLanguages = 1<<(16-runeTableClassBits) - 1
+)
+
+// runeTable maps runes to their classes and languages. The Basic Multilingual
+// Plane is precompiled into a two-level table: the high byte of a rune selects
+// a page and the low byte selects an entry. Identical pages are shared, so the
+// table takes about a hundred kilobytes. Other planes are classified on the fly.
+type runeTable struct {
+	index    [256]uint16
+	pages    []runeTablePage
+	fallback func(r rune) (RuneClass, Language)
+}
+
+// newRuneTable precompiles classify for the Basic Multilingual Plane. If
+// classes or languages do not fit into the table, every rune is classified on
+// the fly.
+func newRuneTable(classify func(r rune) (RuneClass, Language), languages int) *runeTable {
+	t := &runeTable{fallback: classify}
+	if languages > runeTableMaxLanguages {
+		return t
+	}
+	known := make(map[runeTablePage]uint16)
+	for high := 0; high < 256; high++ {
+		var page runeTablePage
+		for low := 0; low < 256; low++ {
+			class, language := classify(rune(high<<8 | low))
+			if class < 0 || class > runeTableClassMask || language < UnknownLanguage || language >= runeTableMaxLanguages {
+				return &runeTable{fallback: classify}
+			}
+			page[low] = uint16(class) | uint16(language+1)<<runeTableClassBits
+		}
+		index, ok := known[page]
+		if !ok {
+			index = uint16(len(t.pages))
+			t.pages = append(t.pages, page)
+			known[page] = index
+		}
+		t.index[high] = index
+	}
+	return t
+}
+
+func (t *runeTable) lookup(r rune) (RuneClass, Language) {
+	if r < 0 || r > 0xffff || t.pages == nil {
+		return t.fallback(r)
+	}
+	entry := t.pages[t.index[r>>8]][r&0xff]
+	return RuneClass(entry & runeTableClassMask), Language(entry>>runeTableClassBits) - 1
+}
+
+// runeTable returns the rune table of registered languages building it if
+// needed. Reading a built table takes no lock.
+func (st *SmartToken) runeTable() *runeTable {
+	if t, _ := st.table.Load().(*runeTable); t != nil {
+		return t
+	}
+	st.tableMutex.Lock()
+	defer st.tableMutex.Unlock()
+	t, _ := st.table.Load().(*runeTable)
+	if t == nil {
+		t = newRuneTable(st.classify, len(st.languages))
+		st.table.Store(t)
+	}
+	return t
+}
+
+func (st *SmartToken) resetRuneTable() {
+	st.tableMutex.Lock()
+	st.table.Store((*runeTable)(nil))
+	st.tableMutex.Unlock()
+}
+
+// classifySlow returns the class of the rune and its language if it is a letter.
+func (st *SmartToken) classifySlow(r rune) (RuneClass, Language) {
+	class := st.getRuneClass(r)
+	if class == Letter {
+		return class, st.getLanguage(r)
+	}
+	return class, UnknownLanguage
+}
@@ -0,0 +1,86 @@
+package gotoken
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var benchmarkScripts = []*unicode.RangeTable{
+	unicode.Latin, unicode.Cyrillic, unicode.Greek, unicode.Armenian,
+	unicode.Hebrew, unicode.Arabic, unicode.Devanagari, unicode.Thai,
+	unicode.Georgian, unicode.Hangul, unicode.Hiragana, unicode.Katakana,
+}
+
+const benchmarkText = "hello привет γειά բարեւ שלום مرحبا नमस्ते สวัสดี გამარჯობა 안녕 こんにちは カタカナ 你好 123 ... ☭ 𝔘𝔫𝔦"
+
+func newBenchmarkTokenizer() *SmartToken {
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	for _, script := range benchmarkScripts {
+		st.AddRangeTable(script)
+	}
+	return st
+}
+
+func TestRuneTable(t *testing.T) {
+	assert := assert.New(t)
+	st := newBenchmarkTokenizer()
+
+	table := st.runeTable()
+	for r := rune(0); r <= unicode.MaxRune; r++ {
+		class, language := table.lookup(r)
+		expectedClass, expectedLanguage := st.classifySlow(r)
+		if class != expectedClass || language != expectedLanguage {
+			assert.Fail("wrong rune table entry", "%U: %v %v instead of %v %v", r, class, language, expectedClass, expectedLanguage)
+			break
+		}
+	}
+
+	assert.True(table == st.runeTable(), "rune table is built once")
+
+	st.AddLanguage("han", unicode.Han)
+	_, language := st.runeTable().lookup('你')
+	assert.Equal(Language(len(benchmarkScripts)), language, "rune table is rebuilt")
+}
+
+func BenchmarkClassifyTable(b *testing.B) {
+	st := newBenchmarkTokenizer()
+	table := st.runeTable()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, r := range benchmarkText {
+			table.lookup(r)
+		}
+	}
+}
+
+func BenchmarkClassifySlow(b *testing.B) {
+	st := newBenchmarkTokenizer()
+	for i := 0; i < b.N; i++ {
+		for _, r := range benchmarkText {
+			st.classifySlow(r)
+		}
+	}
+}
+
+func BenchmarkTokenizeString(b *testing.B) {
+	st := newBenchmarkTokenizer()
+	text := strings.Repeat(benchmarkText+" ", 10)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		st.TokenizeString(text)
+	}
+}
+
+func BenchmarkTokenizeStringParallel(b *testing.B) {
+	st := newBenchmarkTokenizer()
+	text := strings.Repeat(benchmarkText+" ", 10)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			st.TokenizeString(text)
+		}
+	})
+}
@@ -60,7 +60,7 @@ func TestTokenizerTransitions(t *testing.T) {
 			output: map[string]SmartTokenInfo{
 				"hello":   SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 5}},
 				"你好":      SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 6}},
-				"hello你好": SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 11}}, // wtf??
+				"hello你好": SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 11}}, // A single script switch: the word takes the language of its suffix.
 			},
 		},
 		tokenizerTestSet{ // UnknownLanguage -> KnownLanguage.
@@ -196,7 +196,7 @@ func TestTokenizerLanguages(t *testing.T) {
 				"。再见":    SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{3, 9}},
 				"你好。再见":  SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 15}},
 				"。再见。":   SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{3, 9}},
-				"你好。再见。": SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"你好。再见。": SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 15}},
 			},
 		},
 	}
@@ -224,16 +224,16 @@ func TestTokenizerDepth(t *testing.T) {
 				"c.d":     SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 3}},
 				".b.":     SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{1, 2}},
 				".c.":     SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{1, 2}},
-				"a.b.":    SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
-				"b.c.":    SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
-				".b.c":    SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
-				".c.d":    SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
-				"a.b.c":   SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
-				"b.c.d":   SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
-				".b.c.":   SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
-				"a.b.c.":  SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
-				".b.c.d":  SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
-				"a.b.c.d": SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"a.b.":    SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 3}},
+				"b.c.":    SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 3}},
+				".b.c":    SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{1, 4}},
+				".c.d":    SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{1, 4}},
+				"a.b.c":   SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 5}},
+				"b.c.d":   SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 5}},
+				".b.c.":   SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{1, 4}},
+				"a.b.c.":  SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 5}},
+				".b.c.d":  SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{1, 6}},
+				"a.b.c.d": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 7}},
 			},
 		},
 		tokenizerTestSet{
@@ -255,13 +255,13 @@ func TestTokenizerDepth(t *testing.T) {
 				"ccc.ddd":     SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 7}},
 				".bbb.":       SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{1, 4}},
 				".ccc.":       SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{1, 4}},
-				"aaa.bbb.":    SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
-				"bbb.ccc.":    SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
-				".bbb.ccc":    SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
-				".ccc.ddd":    SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
-				"aaa.bbb.ccc": SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
-				"bbb.ccc.ddd": SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
-				".bbb.ccc.":   SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"aaa.bbb.":    SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 7}},
+				"bbb.ccc.":    SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 7}},
+				".bbb.ccc":    SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{1, 8}},
+				".ccc.ddd":    SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{1, 8}},
+				"aaa.bbb.ccc": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 11}},
+				"bbb.ccc.ddd": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 11}},
+				".bbb.ccc.":   SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{1, 8}},
 			},
 		},
 		tokenizerTestSet{
@@ -283,3 +283,75 @@ func TestTokenizerDepth(t *testing.T) {
 	}
 	runTokenizerTestSetDepth(testSet, t)
 }
+
+func TestTokenizerRuneClasses(t *testing.T) {
+	testSet := []tokenizerTestSet{
+		tokenizerTestSet{
+			input: "nai\u0308ve", // Combining mark attaches to the letter block.
+			output: map[string]SmartTokenInfo{
+				"nai\u0308ve": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 7}},
+			},
+		},
+		tokenizerTestSet{
+			input: "\u0308a", // Leading mark is a block of its own.
+			output: map[string]SmartTokenInfo{
+				"\u0308":  SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"a":       SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 1}},
+				"\u0308a": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{2, 3}},
+			},
+		},
+		tokenizerTestSet{
+			input: "$5+°", // Currency, digit, symbol, symbol.
+			output: map[string]SmartTokenInfo{
+				"$":    SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"5":    SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"+°":   SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"$5":   SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"5+°":  SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"$5+°": SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+			},
+		},
+		tokenizerTestSet{
+			input: "a_...b", // Connector is not lumped with punctuation.
+			output: map[string]SmartTokenInfo{
+				"a":      SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 1}},
+				"_":      SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"...":    SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"b":      SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 1}},
+				"a_":     SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 1}},
+				"_...":   SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"...b":   SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{3, 4}},
+				"a_...":  SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 1}},
+				"_...b":  SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{4, 5}},
+				"a_...b": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 6}},
+			},
+		},
+		tokenizerTestSet{
+			input: "hi😀", // Emoji never votes.
+			output: map[string]SmartTokenInfo{
+				"hi":  SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 2}},
+				"😀":   SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"hi😀": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 2}},
+			},
+		},
+		tokenizerTestSet{
+			input: "go\U0001f1fa\U0001f1e6\u231a", // A flag and a watch are emoji too.
+			output: map[string]SmartTokenInfo{
+				"go":                           SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 2}},
+				"\U0001f1fa\U0001f1e6\u231a":   SmartTokenInfo{DetectedLanguage: -1, DetectedBase: [2]int{0, 0}},
+				"go\U0001f1fa\U0001f1e6\u231a": SmartTokenInfo{DetectedLanguage: 0, DetectedBase: [2]int{0, 2}},
+			},
+		},
+	}
+	runTokenizerTestSetDepth(testSet, t)
+
+	assert := assert.New(t)
+	st := NewDepthTokenizer(10, 10, 18, 2)
+	for r, class := range map[rune]RuneClass{
+		'a': Letter, '5': Digit, '.': Punct, '\u0308': Mark, '_': Connector,
+		'+': Symbol, '°': Symbol, '$': Currency, '€': Currency, '😀': Emoji, '☀': Emoji, '\u200d': Other,
+		'©': Emoji, '⌚': Emoji, '⏩': Emoji, '〰': Emoji, '〽': Emoji, '㊗': Emoji, '㊙': Emoji, '\U0001f1fa': Emoji, '\U0001f3fd': Emoji,
+	} {
+		assert.Equal(class, st.getRuneClass(r), "class of %q", r)
+	}
+}
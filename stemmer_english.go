@@ -0,0 +1,266 @@
+package gotoken
+
+import (
+	"strings"
+)
+
+// EnglishStemmer implements the Porter2 (Snowball English) stemming algorithm.
+var EnglishStemmer Stemmer = englishStemmer{}
+
+type englishStemmer struct{}
+
+var englishExceptions = map[string]string{
+	"skis": "ski", "skies": "sky", "dying": "die", "lying": "lie", "tying": "tie",
+	"idly": "idl", "gently": "gentl", "ugly": "ugli", "early": "earli", "only": "onli", "singly": "singl",
+	"sky": "sky", "news": "news", "howe": "howe", "atlas": "atlas", "cosmos": "cosmos", "bias": "bias", "andes": "andes",
+}
+
+var englishExceptionsStep1a = map[string]bool{
+	"inning": true, "outing": true, "canning": true, "herring": true,
+	"earring": true, "proceed": true, "exceed": true, "succeed": true,
+}
+
+// englishWord is a word being stemmed with its R1 and R2 regions.
+type englishWord struct {
+	runes []rune
+	r1    int
+	r2    int
+}
+
+func englishVowel(r rune) bool {
+	return strings.ContainsRune("aeiouy", r)
+}
+
+func englishDouble(w []rune) bool {
+	n := len(w)
+	return n >= 2 && w[n-1] == w[n-2] && strings.ContainsRune("bdfgmnprt", w[n-1])
+}
+
+func englishValidLi(r rune) bool {
+	return strings.ContainsRune("cdeghkmnrt", r)
+}
+
+// englishShortSyllable tells whether the word ends with a short syllable.
+func englishShortSyllable(w []rune) bool {
+	n := len(w)
+	if n == 2 {
+		return englishVowel(w[0]) && !englishVowel(w[1])
+	}
+	return n >= 3 && !englishVowel(w[n-3]) && englishVowel(w[n-2]) && !englishVowel(w[n-1]) && !strings.ContainsRune("wxY", w[n-1])
+}
+
+func (w *englishWord) hasSuffix(suffix string) bool {
+	return strings.HasSuffix(string(w.runes), suffix)
+}
+
+// longest returns the longest of suffixes the word ends with.
+func (w *englishWord) longest(suffixes ...string) string {
+	found := ""
+	for _, suffix := range suffixes {
+		if len(suffix) > len(found) && w.hasSuffix(suffix) {
+			found = suffix
+		}
+	}
+	return found
+}
+
+// start returns the rune offset of the suffix.
+func (w *englishWord) start(suffix string) int {
+	return len(w.runes) - len([]rune(suffix))
+}
+
+func (w *englishWord) replace(suffix string, replacement string) {
+	w.runes = append(w.runes[:w.start(suffix)], []rune(replacement)...)
+}
+
+func (w *englishWord) short() bool {
+	return w.r1 >= len(w.runes) && englishShortSyllable(w.runes)
+}
+
+func (w *englishWord) containsVowel(end int) bool {
+	for _, r := range w.runes[:end] {
+		if englishVowel(r) {
+			return true
+		}
+	}
+	return false
+}
+
+// Stem returns the stem of the lowercased word.
+func (englishStemmer) Stem(word string) string {
+	word = strings.ToLower(word)
+	if stem, ok := englishExceptions[word]; ok {
+		return stem
+	}
+	if len([]rune(word)) <= 2 {
+		return word
+	}
+
+	w := &englishWord{runes: []rune(strings.TrimPrefix(word, "'"))}
+	for index, r := range w.runes {
+		if r == 'y' && (index == 0 || englishVowel(w.runes[index-1])) {
+			w.runes[index] = 'Y'
+		}
+	}
+	w.r1 = englishRegion(w.runes, 0)
+	for _, prefix := range []string{"gener", "commun", "arsen"} {
+		if strings.HasPrefix(string(w.runes), prefix) {
+			w.r1 = len(prefix)
+		}
+	}
+	w.r2 = englishRegion(w.runes, w.r1)
+
+	w.step0()
+	w.step1a()
+	if englishExceptionsStep1a[string(w.runes)] {
+		return string(w.runes)
+	}
+	w.step1b()
+	w.step1c()
+	w.step2()
+	w.step3()
+	w.step4()
+	w.step5()
+	return strings.Replace(string(w.runes), "Y", "y", -1)
+}
+
+// englishRegion returns the offset after the first non-vowel following a vowel
+// starting from the given offset.
+func englishRegion(w []rune, from int) int {
+	for index := from + 1; index < len(w); index++ {
+		if !englishVowel(w[index]) && englishVowel(w[index-1]) {
+			return index + 1
+		}
+	}
+	return len(w)
+}
+
+func (w *englishWord) step0() {
+	if suffix := w.longest("'s'", "'s", "'"); suffix != "" {
+		w.replace(suffix, "")
+	}
+}
+
+func (w *englishWord) step1a() {
+	switch suffix := w.longest("sses", "ied", "ies", "s", "us", "ss"); suffix {
+	case "sses":
+		w.replace(suffix, "ss")
+	case "ied", "ies":
+		if w.start(suffix) > 1 {
+			w.replace(suffix, "i")
+		} else {
+			w.replace(suffix, "ie")
+		}
+	case "s":
+		if w.start(suffix) >= 2 && w.containsVowel(w.start(suffix)-1) {
+			w.replace(suffix, "")
+		}
+	}
+}
+
+func (w *englishWord) step1b() {
+	switch suffix := w.longest("eed", "eedly", "ed", "edly", "ing", "ingly"); suffix {
+	case "eed", "eedly":
+		if w.start(suffix) >= w.r1 {
+			w.replace(suffix, "ee")
+		}
+	case "ed", "edly", "ing", "ingly":
+		if !w.containsVowel(w.start(suffix)) {
+			return
+		}
+		w.replace(suffix, "")
+		switch {
+		case w.hasSuffix("at") || w.hasSuffix("bl") || w.hasSuffix("iz"):
+			w.runes = append(w.runes, 'e')
+		case englishDouble(w.runes):
+			w.runes = w.runes[:len(w.runes)-1]
+		case w.short():
+			w.runes = append(w.runes, 'e')
+		}
+	}
+}
+
+func (w *englishWord) step1c() {
+	n := len(w.runes)
+	if n > 2 && (w.runes[n-1] == 'y' || w.runes[n-1] == 'Y') && !englishVowel(w.runes[n-2]) {
+		w.runes[n-1] = 'i'
+	}
+}
+
+var englishStep2 = map[string]string{
+	"tional": "tion", "enci": "ence", "anci": "ance", "abli": "able", "entli": "ent",
+	"izer": "ize", "ization": "ize", "ational": "ate", "ation": "ate", "ator": "ate",
+	"alism": "al", "aliti": "al", "alli": "al", "fulness": "ful", "ousli": "ous",
+	"ousness": "ous", "iveness": "ive", "iviti": "ive", "biliti": "ble", "bli": "ble",
+	"ogi": "og", "fulli": "ful", "lessli": "less", "li": "",
+}
+
+func (w *englishWord) step2() {
+	suffix := w.longestOf(englishStep2)
+	if suffix == "" || w.start(suffix) < w.r1 {
+		return
+	}
+	switch suffix {
+	case "ogi":
+		if w.start(suffix) == 0 || w.runes[w.start(suffix)-1] != 'l' {
+			return
+		}
+	case "li":
+		if w.start(suffix) == 0 || !englishValidLi(w.runes[w.start(suffix)-1]) {
+			return
+		}
+	}
+	w.replace(suffix, englishStep2[suffix])
+}
+
+var englishStep3 = map[string]string{
+	"tional": "tion", "ational": "ate", "alize": "al", "icate": "ic", "iciti": "ic",
+	"ical": "ic", "ful": "", "ness": "", "ative": "",
+}
+
+func (w *englishWord) step3() {
+	suffix := w.longestOf(englishStep3)
+	if suffix == "" || w.start(suffix) < w.r1 {
+		return
+	}
+	if suffix == "ative" && w.start(suffix) < w.r2 {
+		return
+	}
+	w.replace(suffix, englishStep3[suffix])
+}
+
+func (w *englishWord) step4() {
+	suffix := w.longest("al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement",
+		"ment", "ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion")
+	if suffix == "" || w.start(suffix) < w.r2 {
+		return
+	}
+	if suffix == "ion" && (w.start(suffix) == 0 || !strings.ContainsRune("st", w.runes[w.start(suffix)-1])) {
+		return
+	}
+	w.replace(suffix, "")
+}
+
+func (w *englishWord) step5() {
+	n := len(w.runes)
+	switch {
+	case w.hasSuffix("e"):
+		if n-1 >= w.r2 || (n-1 >= w.r1 && !englishShortSyllable(w.runes[:n-1])) {
+			w.runes = w.runes[:n-1]
+		}
+	case w.hasSuffix("l"):
+		if n-1 >= w.r2 && n >= 2 && w.runes[n-2] == 'l' {
+			w.runes = w.runes[:n-1]
+		}
+	}
+}
+
+func (w *englishWord) longestOf(suffixes map[string]string) string {
+	found := ""
+	for suffix := range suffixes {
+		if len(suffix) > len(found) && w.hasSuffix(suffix) {
+			found = suffix
+		}
+	}
+	return found
+}
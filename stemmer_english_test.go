@@ -0,0 +1,28 @@
+package gotoken
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnglishStemmer(t *testing.T) {
+	ga := assert.New(t)
+	vocabulary := map[string]string{
+		"consign": "consign", "consigned": "consign", "consigning": "consign", "consignment": "consign",
+		"consist": "consist", "consisted": "consist", "consistency": "consist", "consistent": "consist",
+		"consistently": "consist", "consisting": "consist", "consists": "consist",
+		"consolation": "consol", "consolations": "consol", "consolatory": "consolatori",
+		"console": "consol", "consoled": "consol", "consoles": "consol", "consolidate": "consolid",
+		"consolidated": "consolid", "consolidating": "consolid", "consoling": "consol", "consolingly": "consol",
+		"consonant": "conson", "conspicuous": "conspicu", "conspicuously": "conspicu",
+		"conspiracy": "conspiraci", "conspirator": "conspir", "conspirators": "conspir", "conspire": "conspir",
+		"constable": "constabl", "constables": "constabl", "constance": "constanc", "constancy": "constanc",
+		"constant": "constant", "generously": "generous", "running": "run", "hopping": "hop", "hoping": "hope",
+		"skies": "sky", "dying": "die", "cries": "cri", "ties": "tie", "caresses": "caress", "gas": "gas",
+		"gaps": "gap", "happily": "happili", "succeeded": "succeed", "Hello": "hello", "by": "by",
+	}
+	for word, stem := range vocabulary {
+		ga.Equal(stem, EnglishStemmer.Stem(word), "stem of '%v'", word)
+	}
+}
@@ -0,0 +1,141 @@
+package gotoken
+
+import (
+	"strings"
+)
+
+// RussianStemmer implements the Snowball Russian stemming algorithm.
+var RussianStemmer Stemmer = russianStemmer{}
+
+type russianStemmer struct{}
+
+// russianEndings is a group of endings. Endings of the first group must be
+// preceded by "а" or "я", which are kept.
+type russianEndings struct {
+	first  []string
+	second []string
+}
+
+var (
+	russianPerfectiveGerund = russianEndings{
+		first:  []string{"в", "вши", "вшись"},
+		second: []string{"ив", "ивши", "ившись", "ыв", "ывши", "ывшись"},
+	}
+	russianAdjective = russianEndings{
+		second: []string{"ее", "ие", "ые", "ое", "ими", "ыми", "ей", "ий", "ый", "ой", "ем", "им", "ым", "ом",
+			"его", "ого", "ему", "ому", "их", "ых", "ую", "юю", "ая", "яя", "ою", "ею"},
+	}
+	russianParticiple = russianEndings{
+		first:  []string{"ем", "нн", "вш", "ющ", "щ"},
+		second: []string{"ивш", "ывш", "ующ"},
+	}
+	russianReflexive = russianEndings{
+		second: []string{"ся", "сь"},
+	}
+	russianVerb = russianEndings{
+		first: []string{"ла", "на", "ете", "йте", "ли", "й", "л", "ем", "н", "ло", "но", "ет", "ют", "ны", "ть", "ешь", "нно"},
+		second: []string{"ила", "ыла", "ена", "ейте", "уйте", "ите", "или", "ыли", "ей", "уй", "ил", "ыл", "им", "ым", "ен",
+			"ило", "ыло", "ено", "ят", "ует", "уют", "ит", "ыт", "ены", "ить", "ыть", "ишь", "ую", "ю"},
+	}
+	russianNoun = russianEndings{
+		second: []string{"а", "ев", "ов", "ие", "ье", "е", "иями", "ями", "ами", "еи", "ии", "и", "ией", "ей", "ой", "ий", "й",
+			"иям", "ям", "ием", "ем", "ам", "ом", "о", "у", "ах", "иях", "ях", "ы", "ь", "ию", "ью", "ю", "ия", "ья", "я"},
+	}
+	russianSuperlative = russianEndings{
+		second: []string{"ейш", "ейше"},
+	}
+	russianDerivational = russianEndings{
+		second: []string{"ост", "ость"},
+	}
+)
+
+func russianVowel(r rune) bool {
+	return strings.ContainsRune("аеиоуыэюя", r)
+}
+
+// remove deletes the longest of the endings found after the limit. If the
+// longest ending is of the first group and is not preceded by "а" or "я" after
+// the limit, nothing is deleted.
+func (e russianEndings) remove(w []rune, limit int) ([]rune, bool) {
+	if limit > len(w) {
+		return w, false
+	}
+	region := string(w[limit:])
+	found := ""
+	first := false
+	for _, ending := range e.first {
+		if len(ending) > len(found) && strings.HasSuffix(region, ending) {
+			found, first = ending, true
+		}
+	}
+	for _, ending := range e.second {
+		if len(ending) > len(found) && strings.HasSuffix(region, ending) {
+			found, first = ending, false
+		}
+	}
+	if found == "" {
+		return w, false
+	}
+	start := len(w) - len([]rune(found))
+	if first && (start-1 < limit || (w[start-1] != 'а' && w[start-1] != 'я')) {
+		return w, false
+	}
+	return w[:start], true
+}
+
+// Stem returns the stem of the lowercased word.
+func (russianStemmer) Stem(word string) string {
+	w := []rune(strings.Replace(strings.ToLower(word), "ё", "е", -1))
+
+	// RV is the region after the first vowel, R2 is the region after the
+	// first non-vowel following a vowel in R1 which is defined the same way.
+	rv := len(w)
+	for index, r := range w {
+		if russianVowel(r) {
+			rv = index + 1
+			break
+		}
+	}
+	r1 := russianRegion(w, 0)
+	r2 := russianRegion(w, r1)
+
+	// Step 1.
+	var ok bool
+	if w, ok = russianPerfectiveGerund.remove(w, rv); !ok {
+		w, _ = russianReflexive.remove(w, rv)
+		if w, ok = russianAdjective.remove(w, rv); ok {
+			w, _ = russianParticiple.remove(w, rv)
+		} else if w, ok = russianVerb.remove(w, rv); !ok {
+			w, _ = russianNoun.remove(w, rv)
+		}
+	}
+
+	// Step 2.
+	if len(w) > rv && w[len(w)-1] == 'и' {
+		w = w[:len(w)-1]
+	}
+
+	// Step 3.
+	w, _ = russianDerivational.remove(w, r2)
+
+	// Step 4.
+	if w, ok = russianSuperlative.remove(w, rv); ok || strings.HasSuffix(string(w), "нн") {
+		if strings.HasSuffix(string(w), "нн") && len(w)-1 > rv {
+			w = w[:len(w)-1]
+		}
+	} else if len(w) > rv && w[len(w)-1] == 'ь' {
+		w = w[:len(w)-1]
+	}
+	return string(w)
+}
+
+// russianRegion returns the offset after the first non-vowel following a vowel
+// starting from the given offset.
+func russianRegion(w []rune, from int) int {
+	for index := from + 1; index < len(w); index++ {
+		if !russianVowel(w[index]) && russianVowel(w[index-1]) {
+			return index + 1
+		}
+	}
+	return len(w)
+}
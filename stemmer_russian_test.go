@@ -0,0 +1,24 @@
+package gotoken
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRussianStemmer(t *testing.T) {
+	ga := assert.New(t)
+	vocabulary := map[string]string{
+		"в": "в", "вавиловка": "вавиловк", "вагон": "вагон", "вагона": "вагон", "вагоне": "вагон",
+		"вагонов": "вагон", "вагоном": "вагон", "вагоны": "вагон", "важная": "важн", "важнее": "важн",
+		"важнейшие": "важн", "важнейшими": "важн", "важничаешь": "важнича", "важно": "важн",
+		"важного": "важн", "важное": "важн", "важной": "важн", "важном": "важн", "важную": "важн",
+		"Вагоны": "вагон", "ёлками": "елк",
+		// Endings are looked for inside RV only: the longest ending "ей" or "ия"
+		// starts before RV, so the shorter one inside it is removed.
+		"чьей": "чье", "змия": "зми", "кия": "ки", "вия": "ви", "чьим": "чьим", "шеи": "ше",
+	}
+	for word, stem := range vocabulary {
+		ga.Equal(stem, RussianStemmer.Stem(word), "stem of '%v'", word)
+	}
+}